@@ -1,22 +1,74 @@
 package main
 
 import (
+	"fmt"
 	"log"
 	"reflect"
+	"strings"
+	"time"
 
 	"github.com/caarlos0/env/v6"
+	"github.com/tonkeeper/tongo/ton"
+
+	"github.com/tonkeeper/claim-api-go/pkg/api"
 )
 
 type Config struct {
 	API struct {
 		Port        int `env:"PORT" envDefault:"7077"`
 		MetricsPort int `env:"METRICS_PORT" envDefault:"9010"`
+		// AdminPort is a port of the admin API, zero disables it.
+		AdminPort int `env:"ADMIN_PORT"`
+		// AdminToken is a bearer token required by the admin API.
+		AdminToken string `env:"ADMIN_TOKEN"`
 	}
 
 	App struct {
 		LogLevel               string `env:"LOG_LEVEL" envDefault:"INFO"`
-		AirdropDataBocFilename string `env:"AIRDROP_FILE,required"`
-		JettonMaster           string `env:"JETTON_MASTER,required"`
+		AirdropDataBocFilename string `env:"AIRDROP_FILE"`
+		AirdropProofStore      string `env:"AIRDROP_PROOF_STORE"`
+		JettonMaster           string `env:"JETTON_MASTER"`
+		// Campaigns is a list of additional campaigns in the form of "<jetton master>=<airdrop file>".
+		Campaigns []string `env:"CAMPAIGNS"`
+		// ProofStores is a list of proof stores in the form of "<jetton master>=<proof store file>".
+		ProofStores             []string      `env:"PROOF_STORES"`
+		MerkleRootCheckInterval time.Duration `env:"MERKLE_ROOT_CHECK_INTERVAL" envDefault:"10m"`
+		ProverWorkers           int           `env:"PROVER_WORKERS"`
+		EmulatorPoolSize        int           `env:"EMULATOR_POOL_SIZE"`
+		StateRefreshInterval    time.Duration `env:"STATE_REFRESH_INTERVAL" envDefault:"1m"`
+		// QueueWaitBudget is how long a request waits for a place in a full prover queue before it gets 503.
+		QueueWaitBudget time.Duration `env:"QUEUE_WAIT_BUDGET" envDefault:"100ms"`
+		RequestTimeout  time.Duration `env:"REQUEST_TIMEOUT" envDefault:"10s"`
+		// ReadinessQueueThreshold is a length of a prover queue at which /readyz fails, zero disables the check.
+		ReadinessQueueThreshold int `env:"READINESS_QUEUE_THRESHOLD" envDefault:"500"`
+		// ShutdownTimeout limits how long in-flight requests and queued proofs are waited for on SIGTERM.
+		ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" envDefault:"30s"`
+		// SnapshotFile is a snapshot created by cmd/snapshot, the API runs without a network if it is set.
+		SnapshotFile string `env:"SNAPSHOT_FILE"`
+		// Backend is either "liteapi" or "indexer".
+		Backend      string `env:"BACKEND" envDefault:"liteapi"`
+		IndexerURL   string `env:"INDEXER_URL"`
+		IndexerToken string `env:"INDEXER_TOKEN"`
+	}
+
+	// Liteservers are taken from LITE_SERVERS, LITESERVER_CONFIG_FILE or the public config of NETWORK.
+	Liteservers struct {
+		// Servers is a list in the form of "<ip>:<port>:<base64 key>,...".
+		Servers             string        `env:"LITE_SERVERS"`
+		ConfigFile          string        `env:"LITESERVER_CONFIG_FILE"`
+		Network             string        `env:"NETWORK" envDefault:"mainnet"`
+		HealthCheckInterval time.Duration `env:"LITESERVER_HEALTH_CHECK_INTERVAL" envDefault:"10s"`
+		FailureThreshold    int           `env:"LITESERVER_FAILURE_THRESHOLD" envDefault:"3"`
+		OpenTimeout         time.Duration `env:"LITESERVER_OPEN_TIMEOUT" envDefault:"30s"`
+	}
+
+	// Tracing exports spans to an OTLP/HTTP collector if TRACING_ENDPOINT is set.
+	Tracing struct {
+		// Endpoint is host:port of a collector.
+		Endpoint    string  `env:"TRACING_ENDPOINT"`
+		Insecure    bool    `env:"TRACING_INSECURE"`
+		SampleRatio float64 `env:"TRACING_SAMPLE_RATIO" envDefault:"1"`
+		ServiceName string  `env:"TRACING_SERVICE_NAME" envDefault:"claim-api"`
 	}
 }
 
@@ -27,3 +79,83 @@ func Load() Config {
 	}
 	return c
 }
+
+// parseCampaigns returns a list of campaigns to serve.
+// The campaign configured with AIRDROP_FILE and JETTON_MASTER goes first, so it is served by the paths
+// without a jetton master prefix.
+func parseCampaigns(c Config) ([]api.CampaignConfig, error) {
+	var campaigns []api.CampaignConfig
+	if c.App.JettonMaster != "" || c.App.AirdropDataBocFilename != "" || c.App.AirdropProofStore != "" {
+		jettonMaster, err := parseJettonMaster(c.App.JettonMaster)
+		if err != nil {
+			return nil, err
+		}
+		campaigns = append(campaigns, api.CampaignConfig{
+			AirdropFilename:    c.App.AirdropDataBocFilename,
+			ProofStoreFilename: c.App.AirdropProofStore,
+			JettonMaster:       jettonMaster,
+		})
+	}
+	for _, value := range c.App.Campaigns {
+		jettonMaster, filename, err := parseKeyValue(value)
+		if err != nil {
+			return nil, err
+		}
+		campaigns = append(campaigns, api.CampaignConfig{
+			AirdropFilename: filename,
+			JettonMaster:    jettonMaster,
+		})
+	}
+	for _, value := range c.App.ProofStores {
+		jettonMaster, filename, err := parseKeyValue(value)
+		if err != nil {
+			return nil, err
+		}
+		found := false
+		for i := range campaigns {
+			if campaigns[i].JettonMaster == jettonMaster {
+				campaigns[i].ProofStoreFilename = filename
+				found = true
+			}
+		}
+		if !found {
+			campaigns = append(campaigns, api.CampaignConfig{
+				ProofStoreFilename: filename,
+				JettonMaster:       jettonMaster,
+			})
+		}
+	}
+	if len(campaigns) == 0 {
+		return nil, fmt.Errorf("either AIRDROP_FILE and JETTON_MASTER or CAMPAIGNS must be set")
+	}
+	for _, campaign := range campaigns {
+		if campaign.AirdropFilename == "" && campaign.ProofStoreFilename == "" {
+			return nil, fmt.Errorf("neither airdrop file nor proof store is set for %v", campaign.JettonMaster.ToRaw())
+		}
+	}
+	return campaigns, nil
+}
+
+// parseKeyValue parses a value in the form of "<jetton master>=<file>".
+func parseKeyValue(value string) (ton.AccountID, string, error) {
+	jettonMaster, filename, ok := strings.Cut(value, "=")
+	if !ok {
+		return ton.AccountID{}, "", fmt.Errorf("invalid value %q, expected <jetton master>=<file>", value)
+	}
+	accountID, err := parseJettonMaster(jettonMaster)
+	if err != nil {
+		return ton.AccountID{}, "", err
+	}
+	return accountID, filename, nil
+}
+
+func parseJettonMaster(jettonMaster string) (ton.AccountID, error) {
+	if jettonMaster == "" {
+		return ton.AccountID{}, fmt.Errorf("jetton master is required")
+	}
+	accountID, err := ton.ParseAccountID(jettonMaster)
+	if err != nil {
+		return ton.AccountID{}, fmt.Errorf("failed to parse jetton master %q: %w", jettonMaster, err)
+	}
+	return accountID, nil
+}
@@ -2,51 +2,98 @@ package main
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus/promhttp"
-	"github.com/tonkeeper/tongo/ton"
+	"github.com/tonkeeper/tongo/config"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
 
 	"github.com/tonkeeper/claim-api-go/pkg/api"
 )
 
-func createLogger(level string) (*zap.Logger, error) {
+// createLogger returns a logger and its level, the level can be changed at runtime by the admin API.
+func createLogger(level string) (*zap.Logger, zap.AtomicLevel, error) {
 	cfg := zap.NewProductionConfig()
 	if level != "" {
 		lvl, err := zapcore.ParseLevel(level)
 		if err != nil {
-			return nil, err
+			return nil, cfg.Level, err
 		}
 		cfg.Level.SetLevel(lvl)
 	}
-	return cfg.Build()
+	logger, err := cfg.Build()
+	return logger, cfg.Level, err
 }
 
 func main() {
 	cfg := Load()
-	logger, err := createLogger(cfg.App.LogLevel)
+	logger, level, err := createLogger(cfg.App.LogLevel)
 	if err != nil {
 		logger.Fatal("createLogger() failed", zap.Error(err))
 	}
 
-	jettonMaster, err := ton.ParseAccountID(cfg.App.JettonMaster)
+	shutdownTracing, err := api.SetupTracing(context.Background(), api.TracingConfig{
+		Endpoint:    cfg.Tracing.Endpoint,
+		Insecure:    cfg.Tracing.Insecure,
+		SampleRatio: cfg.Tracing.SampleRatio,
+		ServiceName: cfg.Tracing.ServiceName,
+	})
 	if err != nil {
-		logger.Fatal("failed to parse jetton master", zap.Error(err))
+		logger.Fatal("failed to set up tracing", zap.Error(err))
+	}
+
+	campaigns, err := parseCampaigns(cfg)
+	if err != nil {
+		logger.Fatal("failed to parse campaigns", zap.Error(err))
+	}
+
+	var liteservers []config.LiteServer
+	if cfg.Liteservers.Servers != "" {
+		liteservers, err = config.ParseLiteServersEnvVar(cfg.Liteservers.Servers)
+		if err != nil {
+			logger.Fatal("failed to parse liteservers", zap.Error(err))
+		}
 	}
 
 	conf := api.Config{
-		AirdropFilename: cfg.App.AirdropDataBocFilename,
-		JettonMaster:    jettonMaster,
+		Campaigns:               campaigns,
+		MerkleRootCheckInterval: cfg.App.MerkleRootCheckInterval,
+		ProverWorkers:           cfg.App.ProverWorkers,
+		EmulatorPoolSize:        cfg.App.EmulatorPoolSize,
+		StateRefreshInterval:    cfg.App.StateRefreshInterval,
+		SnapshotFilename:        cfg.App.SnapshotFile,
+		QueueWaitBudget:         cfg.App.QueueWaitBudget,
+		RequestTimeout:          cfg.App.RequestTimeout,
+		ReadinessQueueThreshold: cfg.App.ReadinessQueueThreshold,
+		Backend: api.BackendConfig{
+			Kind: cfg.App.Backend,
+			Liteservers: api.LiteserverConfig{
+				Servers:             liteservers,
+				ConfigFile:          cfg.Liteservers.ConfigFile,
+				Network:             cfg.Liteservers.Network,
+				HealthCheckInterval: cfg.Liteservers.HealthCheckInterval,
+				FailureThreshold:    cfg.Liteservers.FailureThreshold,
+				OpenTimeout:         cfg.Liteservers.OpenTimeout,
+			},
+			IndexerURL:   cfg.App.IndexerURL,
+			IndexerToken: cfg.App.IndexerToken,
+		},
 	}
 
 	handler, err := api.NewHandler(logger, conf)
 	if err != nil {
 		logger.Fatal("api.NewHandler() failed", zap.Error(err))
 	}
-	handler.Run(context.TODO())
+	// the handler isn't stopped by a signal, it keeps serving in-flight requests until Shutdown.
+	handler.Run(context.Background())
+	go reloadOnSignal(logger, handler)
 	server, err := api.NewServer(logger, handler, fmt.Sprintf(":%v", cfg.API.Port))
 	if err != nil {
 		logger.Fatal("api.NewServer() failed", zap.Error(err))
@@ -55,12 +102,85 @@ func main() {
 		Addr:    fmt.Sprintf(":%v", cfg.API.MetricsPort),
 		Handler: promhttp.Handler(),
 	}
+	var adminServer *api.Server
+	if cfg.API.AdminPort != 0 {
+		adminServer, err = api.NewAdminServer(logger, handler, fmt.Sprintf(":%v", cfg.API.AdminPort), cfg.API.AdminToken, level)
+		if err != nil {
+			logger.Fatal("api.NewAdminServer() failed", zap.Error(err))
+		}
+	}
+	errs := make(chan error, 3)
 	go func() {
 		if err := metricServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
-			logger.Fatal("listen and serve", zap.Error(err))
+			errs <- fmt.Errorf("metrics server: %w", err)
 		}
 	}()
-
+	go func() {
+		if err := server.Run(); err != nil {
+			errs <- fmt.Errorf("api server: %w", err)
+		}
+	}()
+	if adminServer != nil {
+		go func() {
+			if err := adminServer.Run(); err != nil {
+				errs <- fmt.Errorf("admin server: %w", err)
+			}
+		}()
+	}
 	fmt.Printf("running server :%v\n", cfg.API.Port)
-	server.Run()
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGTERM, syscall.SIGINT)
+	exitCode := 0
+	select {
+	case sig := <-signals:
+		logger.Info("shutting down", zap.Stringer("signal", sig))
+	case err := <-errs:
+		logger.Error("server failed", zap.Error(err))
+		exitCode = 1
+	}
+	if err := shutdown(logger, cfg.App.ShutdownTimeout, server, adminServer, handler, shutdownTracing, &metricServer); err != nil {
+		logger.Error("shutdown failed", zap.Error(err))
+		exitCode = 1
+	}
+	os.Exit(exitCode)
+}
+
+// shutdown stops accepting requests, waits for in-flight ones, drains prover queues and flushes spans.
+// The metrics server goes last, so the shutdown can be observed. adminServer is nil if the admin API is disabled.
+func shutdown(logger *zap.Logger, timeout time.Duration, server, adminServer *api.Server, handler *api.Handler, shutdownTracing func(context.Context) error, metricServer *http.Server) error {
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	var errs []error
+	if err := server.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to shut down api server: %w", err))
+	}
+	if adminServer != nil {
+		if err := adminServer.Shutdown(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to shut down admin server: %w", err))
+		}
+	}
+	if err := handler.Shutdown(ctx); err != nil {
+		errs = append(errs, err)
+	}
+	if err := shutdownTracing(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to flush spans: %w", err))
+	}
+	if err := metricServer.Shutdown(ctx); err != nil {
+		errs = append(errs, fmt.Errorf("failed to shut down metrics server: %w", err))
+	}
+	logger.Info("shut down")
+	return errors.Join(errs...)
+}
+
+// reloadOnSignal reloads airdrop dictionaries every time the process receives SIGHUP.
+func reloadOnSignal(logger *zap.Logger, handler *api.Handler) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	for range signals {
+		logger.Info("reloading airdrop dictionaries")
+		if err := handler.Reload(); err != nil {
+			logger.Error("failed to reload airdrop dictionaries", zap.Error(err))
+		}
+	}
 }
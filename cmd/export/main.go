@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+
+	"github.com/tonkeeper/tongo/ton"
+	"go.uber.org/zap"
+
+	"github.com/tonkeeper/claim-api-go/pkg/api"
+)
+
+// export writes responses of GET /wallet/{address} for every account of an airdrop dictionary
+// and all pages of GET /wallets to a directory, so they can be hosted on an object storage or a CDN.
+// Jetton wallets and state inits are computed with a snapshot of the jetton master taken on start
+// or with the given snapshot file, see cmd/snapshot.
+func main() {
+	jettonMaster := flag.String("jetton-master", "", "jetton master address")
+	airdropFilename := flag.String("airdrop", "", "airdrop dictionary BOC file")
+	proofStore := flag.String("proof-store", "", "optional proof store file, see cmd/proofstore")
+	output := flag.String("output", "", "directory to write files to")
+	pageSize := flag.Int("page-size", 1000, "number of wallets in every page of the wallet list")
+	concurrency := flag.Int("concurrency", 16, "number of wallets processed in parallel")
+	snapshot := flag.String("snapshot", "", "optional snapshot file to run without a network, see cmd/snapshot")
+	flag.Parse()
+
+	if *jettonMaster == "" || *airdropFilename == "" || *output == "" {
+		flag.Usage()
+		log.Fatal("-jetton-master, -airdrop and -output are required")
+	}
+	accountID, err := ton.ParseAccountID(*jettonMaster)
+	if err != nil {
+		log.Fatalf("failed to parse jetton master: %v", err)
+	}
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("failed to create logger: %v", err)
+	}
+	conf := api.Config{
+		Campaigns: []api.CampaignConfig{
+			{
+				AirdropFilename:    *airdropFilename,
+				ProofStoreFilename: *proofStore,
+				JettonMaster:       accountID,
+			},
+		},
+		SnapshotFilename: *snapshot,
+	}
+	handler, err := api.NewHandler(logger, conf)
+	if err != nil {
+		logger.Fatal("api.NewHandler() failed", zap.Error(err))
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	handler.Run(ctx)
+
+	options := api.ExportOptions{
+		Dir:         *output,
+		PageSize:    *pageSize,
+		Concurrency: *concurrency,
+	}
+	if err := handler.Export(ctx, accountID, options); err != nil {
+		logger.Fatal("export failed", zap.Error(err))
+	}
+}
@@ -0,0 +1,25 @@
+package main
+
+import (
+	"flag"
+	"log"
+
+	"github.com/tonkeeper/claim-api-go/pkg/prover"
+)
+
+// proofstore generates proofs for every account of an airdrop dictionary
+// and writes them to a file that claim-api can serve without loading the dictionary.
+func main() {
+	airdropFilename := flag.String("airdrop", "", "airdrop dictionary BOC file")
+	output := flag.String("output", "", "proof store file to write")
+	workers := flag.Int("workers", 0, "number of goroutines generating proofs, zero means the number of CPUs")
+	flag.Parse()
+
+	if *airdropFilename == "" || *output == "" {
+		flag.Usage()
+		log.Fatal("both -airdrop and -output are required")
+	}
+	if err := prover.BuildProofStore(*airdropFilename, *output, *workers); err != nil {
+		log.Fatalf("failed to build proof store: %v", err)
+	}
+}
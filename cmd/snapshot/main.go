@@ -0,0 +1,73 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/tonkeeper/tongo/config"
+	"github.com/tonkeeper/tongo/ton"
+	"go.uber.org/zap"
+
+	"github.com/tonkeeper/claim-api-go/pkg/api"
+)
+
+// snapshot pins states of jetton masters, the blockchain config and library cells to a file,
+// so claim-api and export can run without access to the blockchain, see SNAPSHOT_FILE.
+// Liteservers are configured with the same environment variables as claim-api:
+// LITE_SERVERS, LITESERVER_CONFIG_FILE or the public config of NETWORK.
+func main() {
+	jettonMasters := flag.String("jetton-master", "", "comma separated list of jetton master addresses")
+	output := flag.String("output", "", "snapshot file to write")
+	backend := flag.String("backend", api.BackendLiteapi, "either liteapi or indexer")
+	indexerURL := flag.String("indexer-url", "", "base url of an indexer")
+	indexerToken := flag.String("indexer-token", "", "optional bearer token of an indexer")
+	flag.Parse()
+
+	if *jettonMasters == "" || *output == "" {
+		flag.Usage()
+		log.Fatal("both -jetton-master and -output are required")
+	}
+	var accountIDs []ton.AccountID
+	for _, value := range strings.Split(*jettonMasters, ",") {
+		accountID, err := ton.ParseAccountID(strings.TrimSpace(value))
+		if err != nil {
+			log.Fatalf("failed to parse jetton master %q: %v", value, err)
+		}
+		accountIDs = append(accountIDs, accountID)
+	}
+	logger, err := zap.NewProduction()
+	if err != nil {
+		log.Fatalf("failed to create logger: %v", err)
+	}
+	// LITE_SERVERS takes precedence over LITESERVER_CONFIG_FILE as in claim-api.
+	var liteservers []config.LiteServer
+	if value := os.Getenv("LITE_SERVERS"); value != "" {
+		liteservers, err = config.ParseLiteServersEnvVar(value)
+		if err != nil {
+			logger.Fatal("failed to parse liteservers", zap.Error(err))
+		}
+	}
+	b, err := api.NewBackend(logger, api.BackendConfig{
+		Kind: *backend,
+		Liteservers: api.LiteserverConfig{
+			Servers:    liteservers,
+			ConfigFile: os.Getenv("LITESERVER_CONFIG_FILE"),
+			Network:    os.Getenv("NETWORK"),
+		},
+		IndexerURL:   *indexerURL,
+		IndexerToken: *indexerToken,
+	})
+	if err != nil {
+		logger.Fatal("failed to create backend", zap.Error(err))
+	}
+	snapshot, err := api.CreateSnapshot(context.Background(), logger, b, accountIDs)
+	if err != nil {
+		logger.Fatal("failed to create snapshot", zap.Error(err))
+	}
+	if err := snapshot.Write(*output); err != nil {
+		logger.Fatal("failed to write snapshot", zap.Error(err))
+	}
+}
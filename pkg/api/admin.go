@@ -0,0 +1,230 @@
+package api
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tonkeeper/tongo/ton"
+	"go.uber.org/zap"
+
+	"github.com/tonkeeper/claim-api-go/pkg/api/oas"
+	"github.com/tonkeeper/claim-api-go/pkg/utils"
+)
+
+// Names of caches in the admin API.
+const (
+	proofsCacheName            = "proofs"
+	keyNotFoundCacheName       = "key_not_found"
+	claimedCacheName           = "claimed"
+	jettonMasterStateCacheName = "jetton_master_state"
+)
+
+// accountCache is a cache of a campaign keyed by an account.
+type accountCache interface {
+	Stats() utils.CacheStats
+	Del(key ton.AccountID)
+	Purge()
+}
+
+func (c *campaign) caches() map[string]accountCache {
+	return map[string]accountCache{
+		proofsCacheName:      &c.proofsCache,
+		keyNotFoundCacheName: &c.keyNotFoundCache,
+		claimedCacheName:     &c.claimedCache,
+	}
+}
+
+type adminCacheStats struct {
+	// Campaigns maps a jetton master to stats of its caches.
+	Campaigns          map[string]map[string]utils.CacheStats `json:"campaigns"`
+	JettonMasterStates int                                    `json:"jetton_master_states"`
+}
+
+type adminCampaign struct {
+	JettonMaster            string             `json:"jetton_master"`
+	Default                 bool               `json:"default"`
+	AirdropFile             string             `json:"airdrop_file,omitempty"`
+	ProofStoreFile          string             `json:"proof_store_file,omitempty"`
+	Running                 bool               `json:"running"`
+	QueueLength             int                `json:"queue_length"`
+	JettonMasterStateLoaded bool               `json:"jetton_master_state_loaded"`
+	Stats                   *oas.CampaignStats `json:"stats"`
+}
+
+func (h *Handler) cacheStats() adminCacheStats {
+	stats := adminCacheStats{Campaigns: make(map[string]map[string]utils.CacheStats, len(h.campaigns))}
+	for _, c := range h.campaigns {
+		caches := map[string]utils.CacheStats{}
+		for name, cache := range c.caches() {
+			caches[name] = cache.Stats()
+		}
+		stats.Campaigns[c.jettonMaster.ToRaw()] = caches
+	}
+	h.mu.RLock()
+	stats.JettonMasterStates = len(h.jettonMasterStateCache)
+	h.mu.RUnlock()
+	return stats
+}
+
+// invalidateCaches deletes the given key from the named cache of every campaign.
+// An empty name means all caches, a nil key means all keys.
+// Jetton master states are kept in offline mode, a snapshot is the only source of them.
+func (h *Handler) invalidateCaches(name string, key *ton.AccountID) error {
+	if name == jettonMasterStateCacheName {
+		return h.invalidateJettonMasterStates(key)
+	}
+	for _, c := range h.campaigns {
+		caches := c.caches()
+		if name != "" {
+			cache, ok := caches[name]
+			if !ok {
+				return NotFound("cache not found")
+			}
+			caches = map[string]accountCache{name: cache}
+		}
+		for _, cache := range caches {
+			if key == nil {
+				cache.Purge()
+			} else {
+				cache.Del(*key)
+			}
+		}
+	}
+	if name == "" && h.backend != nil {
+		return h.invalidateJettonMasterStates(key)
+	}
+	return nil
+}
+
+// invalidateJettonMasterStates drops cached states of jetton masters along with their emulators,
+// the next request fetches a fresh state.
+func (h *Handler) invalidateJettonMasterStates(jettonMaster *ton.AccountID) error {
+	if h.backend == nil {
+		return BadRequest(errOffline.Error())
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if jettonMaster == nil {
+		h.jettonMasterStateCache = map[ton.AccountID][2]string{}
+		h.emulatorPools = map[ton.AccountID]*emulatorPool{}
+		h.walletTemplates = map[ton.AccountID]*walletTemplate{}
+		h.walletTemplateRetries = map[ton.AccountID]time.Time{}
+		return nil
+	}
+	delete(h.jettonMasterStateCache, *jettonMaster)
+	delete(h.emulatorPools, *jettonMaster)
+	delete(h.walletTemplates, *jettonMaster)
+	delete(h.walletTemplateRetries, *jettonMaster)
+	return nil
+}
+
+func (h *Handler) campaignsMetadata() []adminCampaign {
+	campaigns := make([]adminCampaign, 0, len(h.campaigns))
+	for _, c := range h.campaigns {
+		_, loaded := h.jettonMasterState(c.jettonMaster)
+		campaigns = append(campaigns, adminCampaign{
+			JettonMaster:            c.jettonMaster.ToRaw(),
+			Default:                 c == h.defaultCampaign,
+			AirdropFile:             c.config.AirdropFilename,
+			ProofStoreFile:          c.config.ProofStoreFilename,
+			Running:                 c.prover.Running(),
+			QueueLength:             c.prover.QueueLength(),
+			JettonMasterStateLoaded: loaded,
+			Stats:                   convertStats(c),
+		})
+	}
+	return campaigns
+}
+
+// NewAdminServer creates a server of the admin API, every request must carry the token as a bearer token.
+// The level is changed by PUT /log/level, see zap.AtomicLevel.ServeHTTP.
+func NewAdminServer(log *zap.Logger, handler *Handler, address string, token string, level zap.AtomicLevel) (*Server, error) {
+	if token == "" {
+		return nil, fmt.Errorf("admin token is required")
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /caches", func(w http.ResponseWriter, r *http.Request) {
+		writeAdminResponse(log, w, handler.cacheStats())
+	})
+	invalidate := func(w http.ResponseWriter, r *http.Request) {
+		var key *ton.AccountID
+		if value := r.PathValue("key"); value != "" {
+			accountID, err := ton.ParseAccountID(value)
+			if err != nil {
+				writeAdminError(log, w, BadRequest("failed to parse key"))
+				return
+			}
+			key = &accountID
+		}
+		name := r.PathValue("cache")
+		if err := handler.invalidateCaches(name, key); err != nil {
+			writeAdminError(log, w, err)
+			return
+		}
+		log.Info("caches invalidated", zap.String("cache", name), zap.String("key", r.PathValue("key")))
+		w.WriteHeader(http.StatusNoContent)
+	}
+	mux.HandleFunc("DELETE /caches", invalidate)
+	mux.HandleFunc("DELETE /caches/{cache}", invalidate)
+	mux.HandleFunc("DELETE /caches/{cache}/{key}", invalidate)
+	mux.HandleFunc("POST /refresh", func(w http.ResponseWriter, r *http.Request) {
+		if handler.backend == nil {
+			writeAdminError(log, w, BadRequest(errOffline.Error()))
+			return
+		}
+		// an empty cursor makes the config and every jetton master state fetched again.
+		if err := handler.refreshState(r.Context(), newRefreshCursor()); err != nil {
+			writeAdminError(log, w, err)
+			return
+		}
+		log.Info("state refreshed")
+		w.WriteHeader(http.StatusNoContent)
+	})
+	mux.Handle("/log/level", level)
+	mux.HandleFunc("GET /campaigns", func(w http.ResponseWriter, r *http.Request) {
+		writeAdminResponse(log, w, handler.campaignsMetadata())
+	})
+	return &Server{
+		logger: log,
+		httpServer: &http.Server{
+			Addr:    address,
+			Handler: adminAuthMiddleware(log, token, mux),
+		},
+	}, nil
+}
+
+func adminAuthMiddleware(log *zap.Logger, token string, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		bearer, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(bearer), []byte(token)) != 1 {
+			writeAdminError(log, w, Unauthorized(errors.New("invalid admin token")))
+			return
+		}
+		next.ServeHTTP(w, r)
+	})
+}
+
+func writeAdminResponse(log *zap.Logger, w http.ResponseWriter, value any) {
+	w.Header().Set("Content-Type", "application/json")
+	if err := json.NewEncoder(w).Encode(value); err != nil {
+		log.Warn("failed to write response", zap.Error(err))
+	}
+}
+
+// writeAdminError writes an error the same way as the API does, see Handler.NewError.
+func writeAdminError(log *zap.Logger, w http.ResponseWriter, err error) {
+	var statusErr *oas.ErrorStatusCode
+	if !errors.As(err, &statusErr) {
+		statusErr = InternalError(err)
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusErr.StatusCode)
+	if err := json.NewEncoder(w).Encode(&statusErr.Response); err != nil {
+		log.Warn("failed to write response", zap.Error(err))
+	}
+}
@@ -0,0 +1,89 @@
+package api
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tonkeeper/tongo/ton"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/tonkeeper/claim-api-go/pkg/prover"
+)
+
+func TestAdminServer(t *testing.T) {
+	c, err := newCampaign(zap.NewNop(), CampaignConfig{AirdropFilename: "../prover/testdata/airdropData.boc"}, 1)
+	require.Nil(t, err)
+	h := &Handler{
+		logger:                 zap.NewNop(),
+		campaigns:              map[ton.AccountID]*campaign{c.jettonMaster: c},
+		defaultCampaign:        c,
+		jettonMasterStateCache: map[ton.AccountID][2]string{},
+	}
+	level := zap.NewAtomicLevelAt(zapcore.InfoLevel)
+	s, err := NewAdminServer(zap.NewNop(), h, ":0", "secret", level)
+	require.Nil(t, err)
+
+	do := func(method, path, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, path, strings.NewReader(body))
+		req.Header.Set("Authorization", "Bearer secret")
+		rec := httptest.NewRecorder()
+		s.httpServer.Handler.ServeHTTP(rec, req)
+		return rec
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/caches", nil)
+	req.Header.Set("Authorization", "Bearer wrong")
+	rec := httptest.NewRecorder()
+	s.httpServer.Handler.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusUnauthorized, rec.Code)
+	require.JSONEq(t, `{"error":"invalid admin token"}`, rec.Body.String())
+
+	first := ton.MustParseAccountID("0:050b89727f74efd71e3f5c396c76c6df7ee71aced7c2ec7a8c55bb8bba8d1399")
+	second := ton.MustParseAccountID("0:6ccd325a858c379693fae2bcaab1c2906831a4e10a6c3bb44ee8b615bca1d220")
+	c.cacheProof(c.prover.MerkleRoot(), prover.WalletAirdrop{AccountID: first})
+	c.cacheProof(c.prover.MerkleRoot(), prover.WalletAirdrop{AccountID: second})
+	c.keyNotFoundCache.Set(first, c.prover.MerkleRoot())
+	c.proofsCache.Get(first)
+
+	var stats adminCacheStats
+	rec = do(http.MethodGet, "/caches", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Nil(t, json.Unmarshal(rec.Body.Bytes(), &stats))
+	proofs := stats.Campaigns[c.jettonMaster.ToRaw()][proofsCacheName]
+	require.Equal(t, 2, proofs.Len)
+	require.Equal(t, uint64(1), proofs.Hits)
+
+	rec = do(http.MethodDelete, "/caches/"+proofsCacheName+"/"+first.ToRaw(), "")
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	require.Equal(t, []ton.AccountID{second}, c.proofsCache.Keys())
+	require.Equal(t, 1, c.keyNotFoundCache.Stats().Len)
+
+	require.Equal(t, http.StatusBadRequest, do(http.MethodDelete, "/caches/"+proofsCacheName+"/invalid", "").Code)
+	require.Equal(t, http.StatusNotFound, do(http.MethodDelete, "/caches/unknown", "").Code)
+	// states of jetton masters can't be fetched again without a backend.
+	require.Equal(t, http.StatusBadRequest, do(http.MethodDelete, "/caches/"+jettonMasterStateCacheName, "").Code)
+	require.Equal(t, http.StatusBadRequest, do(http.MethodPost, "/refresh", "").Code)
+
+	rec = do(http.MethodDelete, "/caches", "")
+	require.Equal(t, http.StatusNoContent, rec.Code)
+	require.Equal(t, 0, c.proofsCache.Stats().Len)
+	require.Equal(t, 0, c.keyNotFoundCache.Stats().Len)
+
+	rec = do(http.MethodPut, "/log/level", `{"level":"debug"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, zapcore.DebugLevel, level.Level())
+
+	var campaigns []adminCampaign
+	rec = do(http.MethodGet, "/campaigns", "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Nil(t, json.Unmarshal(rec.Body.Bytes(), &campaigns))
+	require.Len(t, campaigns, 1)
+	require.True(t, campaigns[0].Default)
+	require.Equal(t, "../prover/testdata/airdropData.boc", campaigns[0].AirdropFile)
+	require.Equal(t, c.prover.MerkleRoot().Hex(), campaigns[0].Stats.MerkleRoot)
+}
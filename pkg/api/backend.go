@@ -0,0 +1,91 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tonkeeper/tongo/boc"
+	"github.com/tonkeeper/tongo/liteapi"
+	"github.com/tonkeeper/tongo/tlb"
+	"github.com/tonkeeper/tongo/ton"
+	"go.uber.org/zap"
+)
+
+// Backend is a source of the blockchain state required by Handler.
+// Method signatures match *liteapi.Client, so the client is a Backend as is.
+type Backend interface {
+	GetAccountState(ctx context.Context, accountID ton.AccountID) (tlb.ShardAccount, error)
+	GetConfigAll(ctx context.Context, mode liteapi.ConfigMode) (tlb.ConfigParams, error)
+	GetLibraries(ctx context.Context, libraryList []ton.Bits256) (map[ton.Bits256]*boc.Cell, error)
+}
+
+const (
+	// BackendLiteapi talks to a pool of liteservers, see LiteserverConfig.
+	BackendLiteapi = "liteapi"
+	// BackendIndexer talks to an HTTP indexer implementing tonapi v2 blockchain methods.
+	BackendIndexer = "indexer"
+)
+
+type BackendConfig struct {
+	// Kind is either BackendLiteapi or BackendIndexer, empty means BackendLiteapi.
+	Kind        string
+	Liteservers LiteserverConfig
+	// IndexerURL is a base URL of an indexer, for example "https://tonapi.io".
+	IndexerURL string
+	// IndexerToken is an optional bearer token of an indexer.
+	IndexerToken string
+}
+
+// NewBackend creates a backend of the configured kind.
+func NewBackend(logger *zap.Logger, config BackendConfig) (Backend, error) {
+	switch config.Kind {
+	case "", BackendLiteapi:
+		return NewLiteserverPool(logger, config.Liteservers)
+	case BackendIndexer:
+		if config.IndexerURL == "" {
+			return nil, fmt.Errorf("indexer url is required")
+		}
+		return NewIndexerBackend(config.IndexerURL, config.IndexerToken), nil
+	default:
+		return nil, fmt.Errorf("unknown backend %q", config.Kind)
+	}
+}
+
+// backendRunner is implemented by backends doing background work, it is started by Handler.Run.
+type backendRunner interface {
+	Run(ctx context.Context)
+}
+
+// keyBlockSource is implemented by backends that report the last key block of the masterchain.
+// The blockchain config changes only in key blocks, so the config is fetched again only after a new one.
+type keyBlockSource interface {
+	LastKeyBlockSeqno(ctx context.Context) (uint32, error)
+}
+
+// lastKeyBlockSeqno returns the seqno of the last key block as of the given masterchain block.
+func lastKeyBlockSeqno(seqno uint32, keyBlock bool, prevKeyBlockSeqno uint32) uint32 {
+	if keyBlock {
+		return seqno
+	}
+	return prevKeyBlockSeqno
+}
+
+// activeAccount builds the state of an active account the same way a liteserver returns it.
+func activeAccount(accountID ton.AccountID, code, data *boc.Cell, lt uint64) tlb.ShardAccount {
+	var account tlb.ShardAccount
+	account.LastTransLt = lt
+	account.Account.SumType = "Account"
+	account.Account.Account.Addr = accountID.ToMsgAddress()
+	account.Account.Account.Storage.LastTransLt = lt
+	state := &account.Account.Account.Storage.State
+	state.SumType = "AccountActive"
+	if code != nil {
+		state.AccountActive.StateInit.Code.Exists = true
+		state.AccountActive.StateInit.Code.Value.Value = *code
+	}
+	if data != nil {
+		state.AccountActive.StateInit.Data.Exists = true
+		state.AccountActive.StateInit.Data.Value.Value = *data
+	}
+	return account
+}
@@ -0,0 +1,207 @@
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/tonkeeper/tongo/boc"
+	"github.com/tonkeeper/tongo/liteapi"
+	"github.com/tonkeeper/tongo/tlb"
+	"github.com/tonkeeper/tongo/ton"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// errIndexerNotFound is returned for 404 responses of an indexer.
+var errIndexerNotFound = errors.New("not found")
+
+// IndexerBackend reads the blockchain state from an HTTP indexer implementing tonapi v2 blockchain methods.
+// Unlike liteapi, an indexer doesn't provide proofs, so it must be trusted.
+type IndexerBackend struct {
+	url    string
+	token  string
+	client *http.Client
+}
+
+var _ Backend = (*IndexerBackend)(nil)
+
+func NewIndexerBackend(url string, token string) *IndexerBackend {
+	return &IndexerBackend{
+		url:    strings.TrimSuffix(url, "/"),
+		token:  token,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+type indexerAccount struct {
+	Code              string `json:"code"`
+	Data              string `json:"data"`
+	LastTransactionLt uint64 `json:"last_transaction_lt"`
+	Status            string `json:"status"`
+}
+
+type indexerConfig struct {
+	Raw string `json:"raw"`
+}
+
+type indexerLibrary struct {
+	Boc string `json:"boc"`
+}
+
+type indexerBlock struct {
+	Seqno             uint32 `json:"seqno"`
+	KeyBlock          bool   `json:"key_block"`
+	PrevKeyBlockSeqno uint32 `json:"prev_key_block_seqno"`
+}
+
+type indexerStatus struct {
+	RestOnline bool `json:"rest_online"`
+}
+
+func (b *IndexerBackend) GetAccountState(ctx context.Context, accountID ton.AccountID) (tlb.ShardAccount, error) {
+	var account indexerAccount
+	err := b.get(ctx, "/v2/blockchain/accounts/"+accountID.ToRaw(), &account)
+	if errors.Is(err, errIndexerNotFound) {
+		return tlb.ShardAccount{Account: tlb.Account{SumType: "AccountNone"}}, nil
+	}
+	if err != nil {
+		return tlb.ShardAccount{}, err
+	}
+	switch account.Status {
+	case "active":
+	case "nonexist":
+		return tlb.ShardAccount{Account: tlb.Account{SumType: "AccountNone"}, LastTransLt: account.LastTransactionLt}, nil
+	case "uninit", "frozen":
+		// the handler runs get-methods of active accounts only, so a frozen state hash is omitted.
+		state := activeAccount(accountID, nil, nil, account.LastTransactionLt)
+		state.Account.Account.Storage.State.SumType = "AccountUninit"
+		if account.Status == "frozen" {
+			state.Account.Account.Storage.State.SumType = "AccountFrozen"
+		}
+		return state, nil
+	default:
+		return tlb.ShardAccount{}, fmt.Errorf("unknown status %q of %v", account.Status, accountID.ToRaw())
+	}
+	code, err := decodeHexCell(account.Code)
+	if err != nil {
+		return tlb.ShardAccount{}, fmt.Errorf("invalid code of %v: %w", accountID.ToRaw(), err)
+	}
+	data, err := decodeHexCell(account.Data)
+	if err != nil {
+		return tlb.ShardAccount{}, fmt.Errorf("invalid data of %v: %w", accountID.ToRaw(), err)
+	}
+	return activeAccount(accountID, code, data, account.LastTransactionLt), nil
+}
+
+// GetConfigAll ignores the mode and always returns the whole config.
+func (b *IndexerBackend) GetConfigAll(ctx context.Context, mode liteapi.ConfigMode) (tlb.ConfigParams, error) {
+	var config indexerConfig
+	if err := b.get(ctx, "/v2/blockchain/config", &config); err != nil {
+		return tlb.ConfigParams{}, err
+	}
+	cell, err := decodeHexCell(config.Raw)
+	if err != nil {
+		return tlb.ConfigParams{}, fmt.Errorf("invalid config: %w", err)
+	}
+	var params tlb.ConfigParams
+	if err := tlb.Unmarshal(cell, &params.Config); err != nil {
+		return tlb.ConfigParams{}, fmt.Errorf("invalid config: %w", err)
+	}
+	return params, nil
+}
+
+func (b *IndexerBackend) GetLibraries(ctx context.Context, libraryList []ton.Bits256) (map[ton.Bits256]*boc.Cell, error) {
+	libraries := make(map[ton.Bits256]*boc.Cell, len(libraryList))
+	for _, hash := range libraryList {
+		var library indexerLibrary
+		err := b.get(ctx, "/v2/blockchain/libraries/"+hash.Hex(), &library)
+		if errors.Is(err, errIndexerNotFound) {
+			// liteapi omits unknown libraries as well.
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		cell, err := decodeHexCell(library.Boc)
+		if err != nil {
+			return nil, fmt.Errorf("invalid library %v: %w", hash.Hex(), err)
+		}
+		libraries[hash] = cell
+	}
+	return libraries, nil
+}
+
+// LastKeyBlockSeqno returns the seqno of the last key block of the masterchain.
+func (b *IndexerBackend) LastKeyBlockSeqno(ctx context.Context) (uint32, error) {
+	var block indexerBlock
+	if err := b.get(ctx, "/v2/blockchain/masterchain-head", &block); err != nil {
+		return 0, err
+	}
+	return lastKeyBlockSeqno(block.Seqno, block.KeyBlock, block.PrevKeyBlockSeqno), nil
+}
+
+// Ping checks that the indexer is online.
+func (b *IndexerBackend) Ping(ctx context.Context) error {
+	var status indexerStatus
+	if err := b.get(ctx, "/v2/status", &status); err != nil {
+		return err
+	}
+	if !status.RestOnline {
+		return fmt.Errorf("indexer is offline")
+	}
+	return nil
+}
+
+func (b *IndexerBackend) get(ctx context.Context, path string, value any) (err error) {
+	ctx, span := tracer.Start(ctx, "indexer.get", trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("path", path)))
+	defer func() {
+		// a missing account or library is a valid answer.
+		if errors.Is(err, errIndexerNotFound) {
+			span.End()
+			return
+		}
+		endSpan(span, err)
+	}()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, b.url+path, nil)
+	if err != nil {
+		return err
+	}
+	if b.token != "" {
+		req.Header.Set("Authorization", "Bearer "+b.token)
+	}
+	resp, err := b.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	switch {
+	case resp.StatusCode == http.StatusNotFound:
+		return errIndexerNotFound
+	case resp.StatusCode != http.StatusOK:
+		return fmt.Errorf("indexer returned %v for %v", resp.Status, path)
+	}
+	return json.NewDecoder(resp.Body).Decode(value)
+}
+
+func decodeHexCell(s string) (*boc.Cell, error) {
+	content, err := hex.DecodeString(s)
+	if err != nil {
+		return nil, err
+	}
+	cells, err := boc.DeserializeBoc(content)
+	if err != nil {
+		return nil, err
+	}
+	if len(cells) != 1 {
+		return nil, fmt.Errorf("expected 1 root cell, got %v", len(cells))
+	}
+	return cells[0], nil
+}
@@ -0,0 +1,100 @@
+package api
+
+import (
+	"context"
+	"sync"
+
+	"github.com/tonkeeper/tongo/boc"
+	"github.com/tonkeeper/tongo/liteapi"
+	"github.com/tonkeeper/tongo/tlb"
+	"github.com/tonkeeper/tongo/ton"
+)
+
+// MemoryBackend is an in-memory Backend for tests.
+// Accounts that haven't been set are reported as nonexistent.
+type MemoryBackend struct {
+	mu        sync.RWMutex
+	accounts  map[ton.AccountID]tlb.ShardAccount
+	config    tlb.ConfigParams
+	libraries map[ton.Bits256]*boc.Cell
+	// keyBlock is incremented by every SetConfig, as a new config comes with a new key block.
+	keyBlock uint32
+}
+
+var _ Backend = (*MemoryBackend)(nil)
+
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{
+		accounts:  map[ton.AccountID]tlb.ShardAccount{},
+		libraries: map[ton.Bits256]*boc.Cell{},
+	}
+}
+
+// SetAccount makes the given account active with the given code and data.
+func (b *MemoryBackend) SetAccount(accountID ton.AccountID, code, data *boc.Cell, lt uint64) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.accounts[accountID] = activeAccount(accountID, code, data, lt)
+}
+
+// SetConfig sets the config from a BOC of the config dictionary as returned by getConfig.
+func (b *MemoryBackend) SetConfig(config string) error {
+	cells, err := boc.DeserializeBocBase64(config)
+	if err != nil {
+		return err
+	}
+	var params tlb.ConfigParams
+	if err := tlb.Unmarshal(cells[0], &params.Config); err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.config = params
+	b.keyBlock++
+	return nil
+}
+
+func (b *MemoryBackend) SetLibrary(library *boc.Cell) error {
+	hash, err := library.Hash256()
+	if err != nil {
+		return err
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.libraries[hash] = library
+	return nil
+}
+
+func (b *MemoryBackend) GetAccountState(ctx context.Context, accountID ton.AccountID) (tlb.ShardAccount, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	account, ok := b.accounts[accountID]
+	if !ok {
+		return tlb.ShardAccount{Account: tlb.Account{SumType: "AccountNone"}}, nil
+	}
+	return account, nil
+}
+
+func (b *MemoryBackend) GetConfigAll(ctx context.Context, mode liteapi.ConfigMode) (tlb.ConfigParams, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.config, nil
+}
+
+func (b *MemoryBackend) LastKeyBlockSeqno(ctx context.Context) (uint32, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	return b.keyBlock, nil
+}
+
+func (b *MemoryBackend) GetLibraries(ctx context.Context, libraryList []ton.Bits256) (map[ton.Bits256]*boc.Cell, error) {
+	b.mu.RLock()
+	defer b.mu.RUnlock()
+	libraries := make(map[ton.Bits256]*boc.Cell, len(libraryList))
+	for _, hash := range libraryList {
+		if library, ok := b.libraries[hash]; ok {
+			libraries[hash] = library
+		}
+	}
+	return libraries, nil
+}
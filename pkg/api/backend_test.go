@@ -0,0 +1,117 @@
+package api
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tonkeeper/tongo/boc"
+	"github.com/tonkeeper/tongo/tlb"
+	"github.com/tonkeeper/tongo/ton"
+)
+
+func hexCell(t *testing.T, cell *boc.Cell) string {
+	content, err := cell.ToBoc()
+	require.Nil(t, err)
+	return hex.EncodeToString(content)
+}
+
+func TestIndexerBackend(t *testing.T) {
+	active := ton.MustParseAccountID("0:6ccd325a858c379693fae2bcaab1c2906831a4e10a6c3bb44ee8b615bca1d220")
+	uninit := ton.MustParseAccountID("0:00fdb15f679957128fd0ee8f740aaca4f37a6877e31d61a454ed9c7604a5c1dc")
+	missing := ton.MustParseAccountID("0:ff41b315c634b4ea4814b9262499567d36e9c7b13da09476f11a41d94e2cb700")
+
+	code := boc.NewCell()
+	require.Nil(t, code.WriteUint(1, 8))
+	data := boc.NewCell()
+	require.Nil(t, data.WriteUint(2, 8))
+	library := boc.NewCell()
+	require.Nil(t, library.WriteUint(3, 8))
+	libraryHash, err := library.Hash256()
+	require.Nil(t, err)
+	cells, err := boc.DeserializeBocBase64(testBlockchainConfig(t))
+	require.Nil(t, err)
+
+	responses := map[string]any{
+		"/v2/blockchain/accounts/" + active.ToRaw(): indexerAccount{
+			Code: hexCell(t, code), Data: hexCell(t, data), LastTransactionLt: 42, Status: "active",
+		},
+		"/v2/blockchain/accounts/" + uninit.ToRaw():                  indexerAccount{LastTransactionLt: 7, Status: "uninit"},
+		"/v2/blockchain/config":                                      indexerConfig{Raw: hexCell(t, cells[0])},
+		"/v2/blockchain/libraries/" + ton.Bits256(libraryHash).Hex(): indexerLibrary{Boc: hexCell(t, library)},
+		"/v2/blockchain/masterchain-head":                            indexerBlock{Seqno: 100, PrevKeyBlockSeqno: 90},
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer token" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		response, ok := responses[r.URL.Path]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		require.Nil(t, json.NewEncoder(w).Encode(response))
+	}))
+	defer server.Close()
+
+	ctx := context.Background()
+	backend := NewIndexerBackend(server.URL+"/", "token")
+
+	account, err := backend.GetAccountState(ctx, active)
+	require.Nil(t, err)
+	require.Equal(t, tlb.AccountActive, account.Account.Status())
+	require.Equal(t, uint64(42), account.LastTransLt)
+	stateInit := account.Account.Account.Storage.State.AccountActive.StateInit
+	require.Equal(t, hexCell(t, code), hexCell(t, &stateInit.Code.Value.Value))
+	require.Equal(t, hexCell(t, data), hexCell(t, &stateInit.Data.Value.Value))
+
+	account, err = backend.GetAccountState(ctx, uninit)
+	require.Nil(t, err)
+	require.Equal(t, tlb.AccountUninit, account.Account.Status())
+	account, err = backend.GetAccountState(ctx, missing)
+	require.Nil(t, err)
+	require.Equal(t, tlb.AccountNone, account.Account.Status())
+
+	config, err := backend.GetConfigAll(ctx, 0)
+	require.Nil(t, err)
+	require.NotEmpty(t, config.Config.Keys())
+
+	keyBlock, err := backend.LastKeyBlockSeqno(ctx)
+	require.Nil(t, err)
+	require.Equal(t, uint32(90), keyBlock)
+
+	libraries, err := backend.GetLibraries(ctx, []ton.Bits256{libraryHash, {1}})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(libraries))
+	require.NotNil(t, libraries[libraryHash])
+
+	_, err = NewIndexerBackend(server.URL, "").GetAccountState(ctx, active)
+	require.ErrorContains(t, err, "401")
+}
+
+func TestMemoryBackend(t *testing.T) {
+	accountID := ton.MustParseAccountID("0:6ccd325a858c379693fae2bcaab1c2906831a4e10a6c3bb44ee8b615bca1d220")
+	backend := NewMemoryBackend()
+	account, err := backend.GetAccountState(context.Background(), accountID)
+	require.Nil(t, err)
+	require.Equal(t, tlb.AccountNone, account.Account.Status())
+
+	backend.SetAccount(accountID, boc.NewCell(), boc.NewCell(), 10)
+	account, err = backend.GetAccountState(context.Background(), accountID)
+	require.Nil(t, err)
+	require.Equal(t, tlb.AccountActive, account.Account.Status())
+
+	// the account can be stored the same way as the one from a liteserver.
+	cell := boc.NewCell()
+	require.Nil(t, tlb.Marshal(cell, account))
+
+	require.Nil(t, backend.SetConfig(testBlockchainConfig(t)))
+	config, err := getConfig(context.Background(), backend)
+	require.Nil(t, err)
+	require.Equal(t, testBlockchainConfig(t), config)
+}
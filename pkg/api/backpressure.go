@@ -0,0 +1,60 @@
+package api
+
+import (
+	"context"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	queueRejectedMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "claim_api_prover_queue_rejected_total",
+		Help: "Number of requests rejected with 503 because a prover queue was full",
+	}, []string{"jetton_master"})
+	requestTimeoutsMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "claim_api_request_timeouts_total",
+		Help: "Number of requests answered with 504 by the stage they timed out at",
+	}, []string{"stage"})
+)
+
+// queueFull is returned when a prover queue stays full longer than the wait budget.
+// Every call builds a new error, so a caller modifying it doesn't affect other requests.
+func queueFull() error {
+	return ServiceUnavailable("service is overloaded, try again later")
+}
+
+// enqueue sends a request to the given queue of the prover of the campaign.
+// A handler goroutine never blocks on a full queue longer than the wait budget of the handler,
+// so an overloaded prover is reported to clients instead of piling up goroutines.
+// Without a wait budget a request waits until its context is done, then ctx.Err() is returned.
+func (h *Handler) enqueue(ctx context.Context, c *campaign, queue chan<- any, req any) error {
+	select {
+	case queue <- req:
+		return nil
+	default:
+	}
+	var budget <-chan time.Time
+	if h.queueWaitBudget > 0 {
+		timer := time.NewTimer(h.queueWaitBudget)
+		defer timer.Stop()
+		budget = timer.C
+	}
+	select {
+	case queue <- req:
+		return nil
+	case <-budget:
+		queueRejectedMetric.WithLabelValues(c.jettonMaster.ToRaw()).Inc()
+		return queueFull()
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// requestTimeout is returned once the context of a request is done at the given stage.
+// A canceled request gets the same error, though nobody is waiting for it.
+func requestTimeout(stage string) error {
+	requestTimeoutsMetric.WithLabelValues(stage).Inc()
+	return GatewayTimeout("timeout")
+}
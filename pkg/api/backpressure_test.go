@@ -0,0 +1,66 @@
+package api
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"github.com/tonkeeper/tongo/ton"
+	"go.uber.org/zap"
+
+	"github.com/tonkeeper/claim-api-go/pkg/prover"
+)
+
+func TestHandler_enqueue(t *testing.T) {
+	c, err := newCampaign(zap.NewNop(), CampaignConfig{AirdropFilename: "../prover/testdata/airdropData.boc"}, 1)
+	require.Nil(t, err)
+	h := &Handler{
+		logger:          zap.NewNop(),
+		campaigns:       map[ton.AccountID]*campaign{c.jettonMaster: c},
+		defaultCampaign: c,
+		queueWaitBudget: 10 * time.Millisecond,
+	}
+	owner := ton.MustParseAccountID("0:050b89727f74efd71e3f5c396c76c6df7ee71aced7c2ec7a8c55bb8bba8d1399")
+
+	// the prover isn't running, so nobody reads its queue.
+	rejected := testutil.ToFloat64(queueRejectedMetric.WithLabelValues(c.jettonMaster.ToRaw()))
+	_, err = h.prove(context.Background(), c, owner)
+	require.Equal(t, queueFull(), err)
+	// every rejection gets its own error.
+	_, again := h.prove(context.Background(), c, owner)
+	require.NotSame(t, err, again)
+	require.Equal(t, rejected+2, testutil.ToFloat64(queueRejectedMetric.WithLabelValues(c.jettonMaster.ToRaw())))
+
+	h.queueWaitBudget = time.Minute
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err = h.prove(ctx, c, owner)
+	require.Equal(t, GatewayTimeout("timeout"), err)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	go c.prover.Run(ctx)
+	proof, err := h.prove(ctx, c, owner)
+	require.Nil(t, err)
+	require.NotEqual(t, prover.WalletAirdrop{}, proof)
+}
+
+func TestRetryAfterMiddleware(t *testing.T) {
+	status := http.StatusServiceUnavailable
+	handler := retryAfterMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(status)
+	}))
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/wallets", nil))
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	require.Equal(t, "1", rec.Header().Get("Retry-After"))
+
+	status = http.StatusOK
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/v1/wallets", nil))
+	require.Equal(t, "", rec.Header().Get("Retry-After"))
+}
@@ -0,0 +1,108 @@
+package api
+
+import (
+	"sync"
+	"time"
+)
+
+type breakerState int
+
+const (
+	// breakerClosed lets all requests through.
+	breakerClosed breakerState = iota
+	// breakerHalfOpen lets a single trial request through, its result decides the next state.
+	breakerHalfOpen
+	// breakerOpen rejects all requests until openTimeout passes.
+	breakerOpen
+)
+
+func (s breakerState) String() string {
+	switch s {
+	case breakerClosed:
+		return "closed"
+	case breakerHalfOpen:
+		return "half-open"
+	default:
+		return "open"
+	}
+}
+
+// circuitBreaker stops sending requests to a server after a number of consecutive failures
+// and lets a trial request through once in a while to find out whether the server has recovered.
+type circuitBreaker struct {
+	threshold   int
+	openTimeout time.Duration
+	now         func() time.Time
+
+	mu       sync.Mutex
+	state    breakerState
+	failures int
+	openedAt time.Time
+	// probing is set while the trial request of the half-open state is running.
+	probing bool
+}
+
+func newCircuitBreaker(threshold int, openTimeout time.Duration) *circuitBreaker {
+	return &circuitBreaker{
+		threshold:   max(threshold, 1),
+		openTimeout: openTimeout,
+		now:         time.Now,
+	}
+}
+
+// allow reports whether a request can be sent.
+// Every allowed request must be followed by success, failure or abort.
+func (b *circuitBreaker) allow() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	switch b.state {
+	case breakerClosed:
+		return true
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.openTimeout {
+			return false
+		}
+		b.state = breakerHalfOpen
+		b.probing = false
+	}
+	if b.probing {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+func (b *circuitBreaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.state = breakerClosed
+	b.failures = 0
+	b.probing = false
+}
+
+// failure records a failed request and reports whether the breaker has just opened.
+func (b *circuitBreaker) failure() bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.probing = false
+	if b.state == breakerOpen || (b.state == breakerClosed && b.failures < b.threshold) {
+		return false
+	}
+	b.state = breakerOpen
+	b.openedAt = b.now()
+	return true
+}
+
+// abort releases a request that says nothing about the server, for example a canceled one.
+func (b *circuitBreaker) abort() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+func (b *circuitBreaker) currentState() breakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
@@ -0,0 +1,46 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func Test_circuitBreaker(t *testing.T) {
+	now := time.Now()
+	b := newCircuitBreaker(2, time.Minute)
+	b.now = func() time.Time { return now }
+
+	require.True(t, b.allow())
+	require.False(t, b.failure())
+	require.True(t, b.allow())
+	require.True(t, b.failure())
+	require.Equal(t, breakerOpen, b.currentState())
+	require.False(t, b.allow())
+
+	// a single trial request is let through once the timeout passes.
+	now = now.Add(time.Minute)
+	require.True(t, b.allow())
+	require.Equal(t, breakerHalfOpen, b.currentState())
+	require.False(t, b.allow())
+	// a failed trial opens the breaker right away.
+	require.True(t, b.failure())
+	require.False(t, b.allow())
+
+	now = now.Add(time.Minute)
+	require.True(t, b.allow())
+	// an aborted trial says nothing about the server, so the next one is let through.
+	b.abort()
+	require.True(t, b.allow())
+	b.success()
+	require.Equal(t, breakerClosed, b.currentState())
+	require.True(t, b.allow())
+	require.True(t, b.allow())
+
+	// a success resets the counter of consecutive failures.
+	require.False(t, b.failure())
+	b.success()
+	require.False(t, b.failure())
+	require.Equal(t, breakerClosed, b.currentState())
+}
@@ -0,0 +1,215 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/tonkeeper/tongo/tlb"
+	"github.com/tonkeeper/tongo/ton"
+	"go.uber.org/zap"
+
+	"github.com/tonkeeper/claim-api-go/pkg/prover"
+	"github.com/tonkeeper/claim-api-go/pkg/utils"
+)
+
+// CampaignConfig describes a single airdrop campaign of a mintless jetton.
+type CampaignConfig struct {
+	AirdropFilename string
+	// ProofStoreFilename is an optional file with precomputed proofs, see prover.BuildProofStore.
+	ProofStoreFilename string
+	JettonMaster       ton.AccountID
+}
+
+// campaign holds everything required to serve claims of a single jetton master.
+type campaign struct {
+	jettonMaster ton.AccountID
+	prover       *prover.Prover
+	// config is kept to describe the campaign in the admin API.
+	config CampaignConfig
+
+	// proofsCache and keyNotFoundCache are tagged with the merkle root of the dictionary
+	// their entries come from, an entry of another dictionary is a miss.
+	proofsCache      utils.Cache[ton.AccountID, rootedProof]
+	keyNotFoundCache utils.Cache[ton.AccountID, tlb.Bits256]
+	// claimedCache keeps claimed flags of jetton wallets by their owners.
+	claimedCache utils.Cache[ton.AccountID, bool]
+	// proofFlights coalesces concurrent proof requests of the same account.
+	proofFlights flightGroup[ton.AccountID, prover.WalletAirdrop]
+	// merkleRoot is a merkleRootStatus of the airdrop dictionary.
+	merkleRoot atomic.Int32
+}
+
+// rootedProof is a proof along with the merkle root of the dictionary it was generated against.
+type rootedProof struct {
+	merkleRoot    tlb.Bits256
+	walletAirdrop prover.WalletAirdrop
+}
+
+const (
+	proofsCacheTTL = 7 * time.Minute
+	// proofRefreshBatch is a number of cached accounts proved at once after a reload.
+	proofRefreshBatch = 100
+	// proofRefreshTimeout limits a single batch, the refresh stops if the prover doesn't keep up.
+	proofRefreshTimeout = 30 * time.Second
+)
+
+func newCampaign(logger *zap.Logger, conf CampaignConfig, proverWorkers int) (*campaign, error) {
+	proverConfig := prover.Config{
+		Filename:           conf.AirdropFilename,
+		ProofStoreFilename: conf.ProofStoreFilename,
+		Workers:            proverWorkers,
+	}
+	p, err := prover.NewProver(logger.With(zap.String("jetton_master", conf.JettonMaster.ToRaw())), proverConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create prover for %v: %w", conf.JettonMaster.ToRaw(), err)
+	}
+	c := &campaign{
+		jettonMaster:     conf.JettonMaster,
+		prover:           p,
+		config:           conf,
+		proofsCache:      utils.NewLRUCache[ton.AccountID, rootedProof](700_000, cacheMetricName("proofs", conf.JettonMaster)),
+		keyNotFoundCache: utils.NewLRUCache[ton.AccountID, tlb.Bits256](700_000, cacheMetricName("keyNotFound", conf.JettonMaster)),
+		claimedCache:     utils.NewLRUCache[ton.AccountID, bool](100_000, cacheMetricName("claimed", conf.JettonMaster)),
+		proofFlights:     flightGroup[ton.AccountID, prover.WalletAirdrop]{name: "proof"},
+	}
+	c.updateStatsMetrics()
+	return c, nil
+}
+
+// cacheMetricName gives caches of every campaign their own series of the cache metrics.
+func cacheMetricName(name string, jettonMaster ton.AccountID) string {
+	return name + "_" + jettonMaster.ToRaw()
+}
+
+// campaign returns a campaign identified by the given jetton master address.
+func (h *Handler) campaign(jettonMaster string) (*campaign, error) {
+	accountID, err := ton.ParseAccountID(jettonMaster)
+	if err != nil {
+		return nil, BadRequest("failed to parse jetton master")
+	}
+	c, ok := h.campaigns[accountID]
+	if !ok {
+		return nil, NotFound("campaign not found")
+	}
+	return c, nil
+}
+
+// cachedProof returns a cached proof of the given account.
+// If the account is known to be missing in the dictionary, it returns NotFound error.
+func (c *campaign) cachedProof(accountID ton.AccountID) (prover.WalletAirdrop, bool, error) {
+	merkleRoot := c.prover.MerkleRoot()
+	if proof, ok := c.proofsCache.Get(accountID); ok && proof.merkleRoot == merkleRoot {
+		return proof.walletAirdrop, true, nil
+	}
+	if root, ok := c.keyNotFoundCache.Get(accountID); ok && root == merkleRoot {
+		return prover.WalletAirdrop{}, false, NotFound("account not found")
+	}
+	return prover.WalletAirdrop{}, false, nil
+}
+
+// handleProofResponse caches a response of the prover and converts its error to an API error.
+func (c *campaign) handleProofResponse(accountID ton.AccountID, resp prover.ProofResponse) (prover.WalletAirdrop, error) {
+	// the dictionary might have been reloaded while the request was in the queue.
+	// Such a response isn't cached, and if a reload slips in after the check,
+	// the entry is tagged with the previous merkle root, so cachedProof never returns it.
+	current := resp.MerkleRoot == c.prover.MerkleRoot()
+	if resp.Err != nil && strings.Contains(resp.Err.Error(), "key is not found") {
+		if current {
+			c.keyNotFoundCache.Set(accountID, resp.MerkleRoot)
+		}
+		return prover.WalletAirdrop{}, NotFound("account not found")
+	}
+	if resp.Err != nil {
+		return prover.WalletAirdrop{}, InternalError(resp.Err)
+	}
+	if current {
+		c.cacheProof(resp.MerkleRoot, resp.WalletAirdrop)
+	}
+	return resp.WalletAirdrop, nil
+}
+
+func (c *campaign) cacheProof(merkleRoot tlb.Bits256, walletAirdrop prover.WalletAirdrop) {
+	c.proofsCache.Set(walletAirdrop.AccountID, rootedProof{merkleRoot: merkleRoot, walletAirdrop: walletAirdrop}, utils.WithExpiration(proofsCacheTTL))
+}
+
+// reload swaps in the airdrop dictionary from the file if its merkle root passes validate.
+// Cached proofs of unchanged accounts are regenerated in the background,
+// until then they are misses and proved on demand.
+func (c *campaign) reload(logger *zap.Logger, validate func(merkleRoot tlb.Bits256) error) error {
+	previous := c.prover.MerkleRoot()
+	cached := c.proofsCache.Keys()
+	update, err := c.prover.Reload(validate)
+	if err != nil {
+		return err
+	}
+	changed := make(map[ton.AccountID]struct{}, len(update.Changed))
+	for _, accountID := range update.Changed {
+		changed[accountID] = struct{}{}
+		c.proofsCache.Del(accountID)
+		c.keyNotFoundCache.Del(accountID)
+	}
+	c.updateStatsMetrics()
+	logger.Info("airdrop dictionary reloaded",
+		zap.String("jetton_master", c.jettonMaster.ToRaw()),
+		zap.String("merkle_root", update.MerkleRoot.Hex()),
+		zap.Int("changed", len(update.Changed)))
+	if update.MerkleRoot == previous {
+		return nil
+	}
+	refresh := make([]ton.AccountID, 0, len(cached))
+	for _, accountID := range cached {
+		if _, ok := changed[accountID]; !ok {
+			refresh = append(refresh, accountID)
+		}
+	}
+	go c.refreshProofs(logger, update.MerkleRoot, refresh)
+	return nil
+}
+
+// refreshProofs regenerates proofs of the given accounts against the dictionary with the given merkle root.
+// Batches go to the enumeration lane, so requests of users are served first.
+// It stops once another dictionary is swapped in.
+func (c *campaign) refreshProofs(logger *zap.Logger, merkleRoot tlb.Bits256, accountIDs []ton.AccountID) {
+	refreshed := 0
+	for start := 0; start < len(accountIDs); start += proofRefreshBatch {
+		if c.prover.MerkleRoot() != merkleRoot {
+			return
+		}
+		batch := accountIDs[start:min(start+proofRefreshBatch, len(accountIDs))]
+		resp, err := c.proveBatch(batch)
+		if err != nil {
+			logger.Warn("failed to refresh proofs",
+				zap.String("jetton_master", c.jettonMaster.ToRaw()),
+				zap.Int("refreshed", refreshed),
+				zap.Error(err))
+			return
+		}
+		for _, r := range resp.Responses {
+			if r.Err == nil && r.MerkleRoot == merkleRoot {
+				c.cacheProof(r.MerkleRoot, r.WalletAirdrop)
+				refreshed++
+			}
+		}
+	}
+	logger.Info("proofs refreshed", zap.String("jetton_master", c.jettonMaster.ToRaw()), zap.Int("refreshed", refreshed))
+}
+
+func (c *campaign) proveBatch(accountIDs []ton.AccountID) (prover.BatchProofResponse, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), proofRefreshTimeout)
+	defer cancel()
+	ch := make(chan prover.BatchProofResponse, 1)
+	select {
+	case <-ctx.Done():
+		return prover.BatchProofResponse{}, ctx.Err()
+	case c.prover.EnumerationQueue() <- prover.BatchProofRequest{Ctx: ctx, AccountIDs: accountIDs, ResponseCh: ch}:
+	}
+	select {
+	case <-ctx.Done():
+		return prover.BatchProofResponse{}, ctx.Err()
+	case resp := <-ch:
+		return resp, nil
+	}
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/tonkeeper/tongo/abi"
+	"github.com/tonkeeper/tongo/tlb"
+	"github.com/tonkeeper/tongo/ton"
+	"github.com/tonkeeper/tongo/tvm"
+
+	"github.com/tonkeeper/claim-api-go/pkg/api/oas"
+	"github.com/tonkeeper/claim-api-go/pkg/prover"
+	"github.com/tonkeeper/claim-api-go/pkg/utils"
+)
+
+// claimedCacheTTL is short because a claim can happen at any moment.
+const claimedCacheTTL = 30 * time.Second
+
+// errNotDeployed is returned by jettonWalletExecutor for a jetton wallet that has no code on-chain.
+var errNotDeployed = errors.New("account is not deployed")
+
+// claimStatus returns the on-chain status of the given airdrop.
+// A claim deploys the jetton wallet of an owner and sets its claimed flag,
+// so a missing wallet means the airdrop hasn't been claimed yet.
+func (h *Handler) claimStatus(ctx context.Context, c *campaign, airdrop prover.WalletAirdrop, jettonWallet ton.AccountID) (oas.WalletInfoClaimStatus, error) {
+	now := time.Now().UTC().Unix()
+	if now < int64(airdrop.Data.StartFrom) {
+		return oas.WalletInfoClaimStatusNotStarted, nil
+	}
+	claimed, ok := c.claimedCache.Get(airdrop.AccountID)
+	if !ok {
+		var err error
+		claimed, err = h.isClaimed(ctx, jettonWallet)
+		if err != nil {
+			return "", err
+		}
+		c.claimedCache.Set(airdrop.AccountID, claimed, utils.WithExpiration(claimedCacheTTL))
+	}
+	switch {
+	case claimed:
+		return oas.WalletInfoClaimStatusClaimed, nil
+	case now > int64(airdrop.Data.ExpireAt):
+		return oas.WalletInfoClaimStatusExpired, nil
+	default:
+		return oas.WalletInfoClaimStatusUnclaimed, nil
+	}
+}
+
+func (h *Handler) isClaimed(ctx context.Context, jettonWallet ton.AccountID) (bool, error) {
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	executor, err := h.jettonWalletExecutor(ctx, jettonWallet)
+	if errors.Is(err, errNotDeployed) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	_, value, err := abi.IsClaimed(ctx, executor, jettonWallet)
+	if err != nil {
+		return false, err
+	}
+	result, ok := value.(abi.IsClaimedResult)
+	if !ok {
+		return false, fmt.Errorf("failed to get claimed flag")
+	}
+	return result.Claimed, nil
+}
+
+// accountExecutor returns an emulator with the current state of the given account.
+// Unlike executor, it never caches the state because a jetton wallet changes with every transfer.
+func (h *Handler) accountExecutor(ctx context.Context, accountID ton.AccountID) (abi.Executor, error) {
+	if h.backend == nil {
+		return nil, errOffline
+	}
+	account, err := h.backend.GetAccountState(ctx, accountID)
+	if err != nil {
+		return nil, err
+	}
+	if account.Account.Status() != tlb.AccountActive {
+		return nil, errNotDeployed
+	}
+	stateInit := account.Account.Account.Storage.State.AccountActive.StateInit
+	if !stateInit.Code.Exists || !stateInit.Data.Exists {
+		return nil, errNotDeployed
+	}
+	code := stateInit.Code.Value.Value
+	data := stateInit.Data.Value.Value
+	c, err := code.ToBocBase64()
+	if err != nil {
+		return nil, err
+	}
+	d, err := data.ToBocBase64()
+	if err != nil {
+		return nil, err
+	}
+	h.mu.RLock()
+	config, emulatorConfig := h.config, h.emulatorConfig
+	h.mu.RUnlock()
+	options := []tvm.Option{tvm.WithLibraryResolver(h.libraries)}
+	if emulatorConfig != nil {
+		options = append(options, tvm.WithConfig(emulatorConfig))
+	}
+	emulator, err := tvm.NewEmulatorFromBOCsBase64(c, d, config, options...)
+	if err != nil {
+		return nil, err
+	}
+	return tracedExecutor{emulator}, nil
+}
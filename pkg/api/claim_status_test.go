@@ -0,0 +1,105 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tonkeeper/tongo/abi"
+	"github.com/tonkeeper/tongo/tlb"
+	"github.com/tonkeeper/tongo/ton"
+	"go.uber.org/zap"
+
+	"github.com/tonkeeper/claim-api-go/pkg/api/oas"
+	"github.com/tonkeeper/claim-api-go/pkg/prover"
+	"github.com/tonkeeper/claim-api-go/pkg/utils"
+)
+
+type mockExecutor struct {
+	claimed bool
+	calls   int
+}
+
+func (e *mockExecutor) RunSmcMethodByID(ctx context.Context, accountID ton.AccountID, methodID int, params tlb.VmStack) (uint32, tlb.VmStack, error) {
+	e.calls++
+	if methodID != 122284 {
+		return 0, nil, fmt.Errorf("unexpected method %v", methodID)
+	}
+	flag := int64(0)
+	if e.claimed {
+		flag = -1
+	}
+	value := tlb.VmStackValue{SumType: "VmStkInt", VmStkInt: tlb.Int257(*big.NewInt(flag))}
+	return 0, tlb.VmStack{value}, nil
+}
+
+func TestHandler_claimStatus(t *testing.T) {
+	now := time.Now().Unix()
+	open := prover.AirdropData{Amount: 100, StartFrom: tlb.Uint48(now - 100), ExpireAt: tlb.Uint48(now + 100)}
+	notStarted := prover.AirdropData{Amount: 100, StartFrom: tlb.Uint48(now + 100), ExpireAt: tlb.Uint48(now + 200)}
+	expired := prover.AirdropData{Amount: 100, StartFrom: tlb.Uint48(now - 200), ExpireAt: tlb.Uint48(now - 100)}
+
+	tests := []struct {
+		name      string
+		data      prover.AirdropData
+		deployed  bool
+		claimed   bool
+		want      oas.WalletInfoClaimStatus
+		wantCalls int
+	}{
+		{name: "not deployed", data: open, want: oas.WalletInfoClaimStatusUnclaimed},
+		{name: "deployed", data: open, deployed: true, want: oas.WalletInfoClaimStatusUnclaimed, wantCalls: 1},
+		{name: "claimed", data: open, deployed: true, claimed: true, want: oas.WalletInfoClaimStatusClaimed, wantCalls: 1},
+		{name: "not started", data: notStarted, deployed: true, want: oas.WalletInfoClaimStatusNotStarted},
+		{name: "expired", data: expired, want: oas.WalletInfoClaimStatusExpired},
+		{name: "claimed before expiration", data: expired, deployed: true, claimed: true, want: oas.WalletInfoClaimStatusClaimed, wantCalls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			executor := &mockExecutor{claimed: tt.claimed}
+			h := &Handler{
+				logger: zap.NewNop(),
+				jettonWalletExecutor: func(ctx context.Context, jettonWallet ton.AccountID) (abi.Executor, error) {
+					if !tt.deployed {
+						return nil, errNotDeployed
+					}
+					return executor, nil
+				},
+			}
+			c := &campaign{claimedCache: utils.NewLRUCache[ton.AccountID, bool](10, "claimed")}
+			airdrop := prover.WalletAirdrop{
+				AccountID: ton.MustParseAccountID("0:6ccd325a858c379693fae2bcaab1c2906831a4e10a6c3bb44ee8b615bca1d220"),
+				Data:      tt.data,
+			}
+			jettonWallet := ton.MustParseAccountID("0:00fdb15f679957128fd0ee8f740aaca4f37a6877e31d61a454ed9c7604a5c1dc")
+			for i := 0; i < 2; i++ {
+				status, err := h.claimStatus(context.Background(), c, airdrop, jettonWallet)
+				require.Nil(t, err)
+				require.Equal(t, tt.want, status)
+			}
+			// the second call is served from the cache.
+			require.Equal(t, tt.wantCalls, executor.calls)
+		})
+	}
+}
+
+func TestHandler_claimStatusError(t *testing.T) {
+	h := &Handler{
+		logger: zap.NewNop(),
+		jettonWalletExecutor: func(ctx context.Context, jettonWallet ton.AccountID) (abi.Executor, error) {
+			return nil, fmt.Errorf("liteserver is not available")
+		},
+	}
+	c := &campaign{claimedCache: utils.NewLRUCache[ton.AccountID, bool](10, "claimed")}
+	now := time.Now().Unix()
+	airdrop := prover.WalletAirdrop{
+		Data: prover.AirdropData{StartFrom: tlb.Uint48(now - 100), ExpireAt: tlb.Uint48(now + 100)},
+	}
+	_, err := h.claimStatus(context.Background(), c, airdrop, ton.AccountID{})
+	require.ErrorContains(t, err, "liteserver is not available")
+	_, ok := c.claimedCache.Get(airdrop.AccountID)
+	require.False(t, ok)
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/tonkeeper/tongo/ton"
+)
+
+var coalescedRequestsMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "claim_api_coalesced_requests_total",
+	Help: "Number of requests that joined an in-flight computation instead of starting a new one",
+}, []string{"step"})
+
+// walletKey identifies a jetton wallet of an owner.
+type walletKey struct {
+	jettonMaster ton.AccountID
+	owner        ton.AccountID
+}
+
+// flightGroup coalesces concurrent calls with the same key, so they share a single computation.
+// Unlike singleflight, the computation runs with its own context that is canceled
+// once all callers have given up, so a caller leaving early doesn't fail the others.
+// The zero value is ready to use.
+type flightGroup[K comparable, V any] struct {
+	// name is a step label of the coalesced requests metric.
+	name string
+
+	mu      sync.Mutex
+	flights map[K]*flight[V]
+}
+
+type flight[V any] struct {
+	done   chan struct{}
+	value  V
+	err    error
+	cancel context.CancelFunc
+	// waiters is a number of callers waiting for the result.
+	waiters int
+}
+
+// do returns the result of fn for the given key, fn is called only if there is no call in flight for the key.
+// fn gets a context keeping values of ctx, but not its deadline.
+// If ctx is done before the result is ready, do returns ctx.Err().
+func (g *flightGroup[K, V]) do(ctx context.Context, key K, fn func(ctx context.Context) (V, error)) (V, error) {
+	g.mu.Lock()
+	if g.flights == nil {
+		g.flights = map[K]*flight[V]{}
+	}
+	f, ok := g.flights[key]
+	if ok {
+		coalescedRequestsMetric.WithLabelValues(g.name).Inc()
+	} else {
+		callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+		f = &flight[V]{done: make(chan struct{}), cancel: cancel}
+		g.flights[key] = f
+		go func() {
+			f.value, f.err = fn(callCtx)
+			cancel()
+			g.mu.Lock()
+			if g.flights[key] == f {
+				delete(g.flights, key)
+			}
+			g.mu.Unlock()
+			close(f.done)
+		}()
+	}
+	f.waiters++
+	g.mu.Unlock()
+
+	select {
+	case <-f.done:
+		return f.value, f.err
+	case <-ctx.Done():
+		g.mu.Lock()
+		f.waiters--
+		if f.waiters == 0 {
+			// nobody waits for the result, a new caller starts over.
+			f.cancel()
+			if g.flights[key] == f {
+				delete(g.flights, key)
+			}
+		}
+		g.mu.Unlock()
+		var zero V
+		return zero, ctx.Err()
+	}
+}
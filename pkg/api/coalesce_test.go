@@ -0,0 +1,107 @@
+package api
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFlightGroup(t *testing.T) {
+	var g flightGroup[int, string]
+	var calls atomic.Int32
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (string, error) {
+		calls.Add(1)
+		<-release
+		return "value", nil
+	}
+
+	var wg sync.WaitGroup
+	results := make([]string, 10)
+	for i := range results {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			value, err := g.do(context.Background(), 1, fn)
+			require.Nil(t, err)
+			results[i] = value
+		}(i)
+	}
+	// wait until all callers have joined the flight.
+	require.Eventually(t, func() bool {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		return g.flights[1] != nil && g.flights[1].waiters == len(results)
+	}, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+	require.Equal(t, int32(1), calls.Load())
+	for _, value := range results {
+		require.Equal(t, "value", value)
+	}
+
+	// a finished flight isn't reused.
+	value, err := g.do(context.Background(), 1, fn)
+	require.Nil(t, err)
+	require.Equal(t, "value", value)
+	require.Equal(t, int32(2), calls.Load())
+}
+
+func TestFlightGroup_cancel(t *testing.T) {
+	var g flightGroup[int, string]
+	release := make(chan struct{})
+	fn := func(ctx context.Context) (string, error) {
+		select {
+		case <-ctx.Done():
+			return "", ctx.Err()
+		case <-release:
+			return "value", nil
+		}
+	}
+
+	// the first caller gives up, but the second one still gets the result.
+	ctx, cancel := context.WithCancel(context.Background())
+	first := make(chan error, 1)
+	go func() {
+		_, err := g.do(ctx, 1, fn)
+		first <- err
+	}()
+	second := make(chan string, 1)
+	go func() {
+		value, err := g.do(context.Background(), 1, fn)
+		require.Nil(t, err)
+		second <- value
+	}()
+	require.Eventually(t, func() bool {
+		g.mu.Lock()
+		defer g.mu.Unlock()
+		return g.flights[1] != nil && g.flights[1].waiters == 2
+	}, time.Second, time.Millisecond)
+	cancel()
+	require.ErrorIs(t, <-first, context.Canceled)
+	close(release)
+	require.Equal(t, "value", <-second)
+
+	// once all callers give up, the computation is canceled.
+	canceled := make(chan struct{})
+	ctx, cancel = context.WithCancel(context.Background())
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		cancel()
+	}()
+	_, err := g.do(ctx, 1, func(ctx context.Context) (string, error) {
+		<-ctx.Done()
+		close(canceled)
+		return "", ctx.Err()
+	})
+	require.ErrorIs(t, err, context.Canceled)
+	select {
+	case <-canceled:
+	case <-time.After(time.Second):
+		t.Fatal("computation is not canceled")
+	}
+}
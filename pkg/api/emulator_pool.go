@@ -0,0 +1,179 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/tonkeeper/tongo/abi"
+	"github.com/tonkeeper/tongo/boc"
+	"github.com/tonkeeper/tongo/ton"
+	"github.com/tonkeeper/tongo/tvm"
+)
+
+// emulatorMaxAge limits how long an emulator is reused.
+// An emulator initializes c7 including the current time on its first run and never updates it.
+const emulatorMaxAge = 10 * time.Minute
+
+var (
+	emulatorPoolSizeMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "claim_api_emulator_pool_size",
+		Help: "Maximum number of emulators in the pool of a jetton master",
+	}, []string{"jetton_master"})
+	emulatorPoolInUseMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "claim_api_emulator_pool_in_use",
+		Help: "Number of emulators of a jetton master currently running get-methods",
+	}, []string{"jetton_master"})
+	emulatorAcquireTimeMetric = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "claim_api_emulator_acquire_time",
+		Help:    "Time to get an emulator from the pool, the difference between created and reused is the latency saved",
+		Buckets: []float64{0.00001, 0.0001, 0.001, 0.005, 0.01, 0.05, 0.1, 0.5, 1},
+	}, []string{"result"})
+)
+
+// emulatorPool keeps ready emulators of a jetton master.
+// An emulator isn't safe for concurrent use, so every emulator is used by one caller at a time.
+// A pool is built for a particular state of a jetton master and blockchain config,
+// once either of them changes the pool is replaced with a new one and the old one is left to the GC.
+type emulatorPool struct {
+	jettonMaster ton.AccountID
+	// code, data and config are decoded once when the pool is built and shared by its emulators.
+	code, data *boc.Cell
+	config     *boc.Cell
+	options    []tvm.Option
+
+	idle chan *pooledEmulator
+	// tokens limits the number of emulators created by the pool.
+	tokens chan struct{}
+}
+
+type pooledEmulator struct {
+	emulator *tvm.Emulator
+	created  time.Time
+}
+
+func newEmulatorPool(jettonMaster ton.AccountID, state [2]string, config string, size int, options ...tvm.Option) (*emulatorPool, error) {
+	if size <= 0 {
+		size = runtime.NumCPU()
+	}
+	code, err := boc.DeserializeSinglRootBase64(state[0])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode code of %v: %w", jettonMaster.ToRaw(), err)
+	}
+	data, err := boc.DeserializeSinglRootBase64(state[1])
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode data of %v: %w", jettonMaster.ToRaw(), err)
+	}
+	var configCell *boc.Cell
+	if config != "" {
+		if configCell, err = boc.DeserializeSinglRootBase64(config); err != nil {
+			return nil, fmt.Errorf("failed to decode blockchain config: %w", err)
+		}
+	}
+	emulatorPoolSizeMetric.WithLabelValues(jettonMaster.ToRaw()).Set(float64(size))
+	return &emulatorPool{
+		jettonMaster: jettonMaster,
+		code:         code,
+		data:         data,
+		config:       configCell,
+		options:      options,
+		idle:         make(chan *pooledEmulator, size),
+		tokens:       make(chan struct{}, size),
+	}, nil
+}
+
+// acquire returns an idle emulator, creates a new one if the pool isn't full
+// or waits for an emulator to be released.
+func (p *emulatorPool) acquire(ctx context.Context) (*pooledEmulator, error) {
+	start := time.Now()
+	select {
+	case e := <-p.idle:
+		p.acquired(start, "reused")
+		return e, nil
+	default:
+	}
+	select {
+	case e := <-p.idle:
+		p.acquired(start, "waited")
+		return e, nil
+	case p.tokens <- struct{}{}:
+		emulator, err := tvm.NewEmulator(p.code, p.data, p.config, p.options...)
+		if err != nil {
+			<-p.tokens
+			return nil, err
+		}
+		p.acquired(start, "created")
+		return &pooledEmulator{emulator: emulator, created: time.Now()}, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (p *emulatorPool) acquired(start time.Time, result string) {
+	emulatorAcquireTimeMetric.WithLabelValues(result).Observe(time.Since(start).Seconds())
+	emulatorPoolInUseMetric.WithLabelValues(p.jettonMaster.ToRaw()).Inc()
+}
+
+func (p *emulatorPool) release(e *pooledEmulator) {
+	emulatorPoolInUseMetric.WithLabelValues(p.jettonMaster.ToRaw()).Dec()
+	if time.Since(e.created) > emulatorMaxAge {
+		// the emulator is destroyed by its finalizer.
+		<-p.tokens
+		return
+	}
+	// never blocks because there are no more emulators than tokens.
+	p.idle <- e
+}
+
+// executor returns an emulator of the given jetton master and a function to return it to the pool.
+// The emulator must not be used after the release.
+func (h *Handler) executor(ctx context.Context, id ton.AccountID) (abi.Executor, func(), error) {
+	pool, err := h.emulatorPool(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	e, err := pool.acquire(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	return tracedExecutor{e.emulator}, func() { pool.release(e) }, nil
+}
+
+func (h *Handler) emulatorPool(ctx context.Context, id ton.AccountID) (*emulatorPool, error) {
+	h.mu.RLock()
+	pool, ok := h.emulatorPools[id]
+	h.mu.RUnlock()
+	if ok {
+		return pool, nil
+	}
+	if _, ok := h.jettonMasterState(id); !ok {
+		state, _, err := h.fetchJettonMasterState(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		h.setJettonMasterState(id, state)
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	// the state and config might have changed, so the pool is built from the cached ones.
+	if pool, ok := h.emulatorPools[id]; ok {
+		return pool, nil
+	}
+	options := []tvm.Option{tvm.WithLibraryResolver(h.libraries)}
+	if h.emulatorConfig != nil {
+		options = append(options, tvm.WithConfig(h.emulatorConfig))
+	}
+	pool, err := newEmulatorPool(id, h.jettonMasterStateCache[id], h.config, h.emulatorPoolSize, options...)
+	if err != nil {
+		return nil, err
+	}
+	if h.emulatorPools == nil {
+		h.emulatorPools = map[ton.AccountID]*emulatorPool{}
+	}
+	h.emulatorPools[id] = pool
+	return pool, nil
+}
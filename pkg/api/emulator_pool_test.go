@@ -0,0 +1,98 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tonkeeper/tongo/boc"
+	"github.com/tonkeeper/tongo/ton"
+	"go.uber.org/zap"
+)
+
+func emptyCellBoc(t *testing.T) string {
+	s, err := boc.NewCell().ToBocBase64()
+	require.Nil(t, err)
+	return s
+}
+
+func Test_emulatorPool(t *testing.T) {
+	empty := emptyCellBoc(t)
+	jettonMaster := ton.MustParseAccountID("EQD6Z9DHc5Mx-8PI8I4BjGX0d2NhapaRAK12CgstweNoMint")
+	_, err := newEmulatorPool(jettonMaster, [2]string{empty, "invalid"}, "", 2)
+	require.NotNil(t, err)
+	pool, err := newEmulatorPool(jettonMaster, [2]string{empty, empty}, "", 2)
+	require.Nil(t, err)
+
+	first, err := pool.acquire(context.Background())
+	require.Nil(t, err)
+	second, err := pool.acquire(context.Background())
+	require.Nil(t, err)
+	require.NotSame(t, first.emulator, second.emulator)
+
+	// the pool is full, so the next caller waits for a release.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	_, err = pool.acquire(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	pool.release(first)
+	third, err := pool.acquire(context.Background())
+	require.Nil(t, err)
+	require.Same(t, first.emulator, third.emulator)
+
+	// an old emulator is dropped and its place is taken by a new one.
+	second.created = time.Now().Add(-2 * emulatorMaxAge)
+	pool.release(second)
+	fourth, err := pool.acquire(context.Background())
+	require.Nil(t, err)
+	require.NotSame(t, second.emulator, fourth.emulator)
+}
+
+func TestHandler_emulatorPoolInvalidation(t *testing.T) {
+	empty := emptyCellBoc(t)
+	jettonMaster := ton.MustParseAccountID("EQD6Z9DHc5Mx-8PI8I4BjGX0d2NhapaRAK12CgstweNoMint")
+	h := &Handler{
+		logger:                 zap.NewNop(),
+		jettonMasterStateCache: map[ton.AccountID][2]string{},
+		emulatorPoolSize:       1,
+		walletTemplates:        map[ton.AccountID]*walletTemplate{},
+	}
+	h.setJettonMasterState(jettonMaster, [2]string{empty, empty})
+
+	pool, err := h.emulatorPool(context.Background(), jettonMaster)
+	require.Nil(t, err)
+	same, err := h.emulatorPool(context.Background(), jettonMaster)
+	require.Nil(t, err)
+	require.Same(t, pool, same)
+
+	executor, release, err := h.executor(context.Background(), jettonMaster)
+	require.Nil(t, err)
+	require.NotNil(t, executor)
+
+	// the same state keeps the pool.
+	h.walletTemplates[jettonMaster] = nil
+	h.setJettonMasterState(jettonMaster, [2]string{empty, empty})
+	require.Contains(t, h.walletTemplates, jettonMaster)
+	same, err = h.emulatorPool(context.Background(), jettonMaster)
+	require.Nil(t, err)
+	require.Same(t, pool, same)
+
+	data := boc.NewCell()
+	require.Nil(t, data.WriteUint(1, 8))
+	newData, err := data.ToBocBase64()
+	require.Nil(t, err)
+	h.walletTemplates[jettonMaster] = &walletTemplate{}
+	h.setJettonMasterState(jettonMaster, [2]string{empty, newData})
+	require.NotContains(t, h.walletTemplates, jettonMaster)
+	newPool, err := h.emulatorPool(context.Background(), jettonMaster)
+	require.Nil(t, err)
+	require.NotSame(t, pool, newPool)
+	poolData, err := newPool.data.ToBocBase64()
+	require.Nil(t, err)
+	require.Equal(t, newData, poolData)
+
+	// an emulator of the old pool can be released at any moment.
+	release()
+}
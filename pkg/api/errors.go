@@ -27,6 +27,21 @@ func InternalError(err error) *oas.ErrorStatusCode {
 	}
 }
 
+// ServiceUnavailable is returned when the service is overloaded, see retryAfterMiddleware.
+func ServiceUnavailable(msg string) *oas.ErrorStatusCode {
+	return &oas.ErrorStatusCode{
+		StatusCode: http.StatusServiceUnavailable,
+		Response:   oas.Error{Error: msg},
+	}
+}
+
+func GatewayTimeout(msg string) *oas.ErrorStatusCode {
+	return &oas.ErrorStatusCode{
+		StatusCode: http.StatusGatewayTimeout,
+		Response:   oas.Error{Error: msg},
+	}
+}
+
 func Unauthorized(err error) *oas.ErrorStatusCode {
 	return &oas.ErrorStatusCode{
 		StatusCode: http.StatusUnauthorized,
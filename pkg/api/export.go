@@ -0,0 +1,159 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/tonkeeper/tongo/ton"
+	"go.uber.org/zap"
+
+	"github.com/tonkeeper/claim-api-go/pkg/api/oas"
+	"github.com/tonkeeper/claim-api-go/pkg/prover"
+)
+
+// ExportOptions configures a static export of a campaign.
+type ExportOptions struct {
+	// Dir is a directory to write files to.
+	Dir string
+	// PageSize is a number of wallets in every page of the wallet list.
+	PageSize int
+	// Concurrency is a number of wallets processed in parallel.
+	Concurrency int
+}
+
+// Export writes all claims of the campaign of the given jetton master as static files,
+// so they can be served from an object storage or a CDN:
+//
+//	<dir>/wallet/<owner>.json   - the same response as GET /wallet/{address}
+//	<dir>/wallets/<from>.json   - the same response as GET /wallets?next_from=<from>&count=<page size>
+//
+// Addresses in file names are in the raw form, the first page of the wallet list
+// starts from the zero address "0:0000...0000".
+// Unlike the API, a custom payload is always included, because the files outlive the claim window
+// that was open during the export. A jetton wallet rejects claims outside the window anyway.
+// The handler must be running.
+func (h *Handler) Export(ctx context.Context, jettonMaster ton.AccountID, options ExportOptions) error {
+	c, ok := h.campaigns[jettonMaster]
+	if !ok {
+		return fmt.Errorf("campaign %v not found", jettonMaster.ToRaw())
+	}
+	if options.PageSize <= 0 {
+		return fmt.Errorf("invalid page size: %v", options.PageSize)
+	}
+	for _, dir := range []string{"wallet", "wallets"} {
+		if err := os.MkdirAll(filepath.Join(options.Dir, dir), 0755); err != nil {
+			return err
+		}
+	}
+	concurrency := max(options.Concurrency, 1)
+	from := ton.AccountID{}
+	total := 0
+	for {
+		page, err := h.wallets(ctx, c, from.ToRaw(), options.PageSize)
+		if err != nil {
+			return fmt.Errorf("failed to get wallets from %v: %w", from.ToRaw(), err)
+		}
+		if err := writeJSON(filepath.Join(options.Dir, "wallets", from.ToRaw()+".json"), page); err != nil {
+			return err
+		}
+		if err := h.exportWallets(ctx, c, page, options.Dir, concurrency); err != nil {
+			return err
+		}
+		total += len(page.Wallets)
+		h.logger.Info("exported wallets", zap.Int("total", total))
+		if page.NextFrom == "" {
+			return nil
+		}
+		from, err = ton.ParseAccountID(page.NextFrom)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+func (h *Handler) exportWallets(ctx context.Context, c *campaign, page *oas.WalletList, dir string, concurrency int) error {
+	owners := make(chan string)
+	errs := make(chan error, concurrency)
+	var wg sync.WaitGroup
+	for i := 0; i < concurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for owner := range owners {
+				if err := h.exportWallet(ctx, c, owner, dir); err != nil {
+					errs <- err
+					return
+				}
+			}
+		}()
+	}
+	var err error
+loop:
+	for _, item := range page.Wallets {
+		select {
+		case err = <-errs:
+			break loop
+		case owners <- item.Owner:
+		}
+	}
+	close(owners)
+	wg.Wait()
+	if err != nil {
+		return err
+	}
+	select {
+	case err = <-errs:
+		return err
+	default:
+		return nil
+	}
+}
+
+func (h *Handler) exportWallet(ctx context.Context, c *campaign, owner string, dir string) error {
+	accountID, err := ton.ParseAccountID(owner)
+	if err != nil {
+		return err
+	}
+	responseCh := make(chan prover.ProofResponse, 1)
+	// unlike Handler.enqueue, an export has no wait budget, it waits for the prover as long as it takes.
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case c.prover.Queue() <- prover.ProofRequest{
+		Ctx:        ctx,
+		AccountID:  accountID,
+		ResponseCh: responseCh,
+	}:
+	}
+	var resp prover.ProofResponse
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case resp = <-responseCh:
+	}
+	if resp.Err != nil {
+		return fmt.Errorf("failed to prove %v: %w", owner, resp.Err)
+	}
+	info, err := h.convertToWalletInfo(ctx, c, resp.WalletAirdrop, false)
+	if err != nil {
+		return fmt.Errorf("failed to convert %v: %w", owner, err)
+	}
+	if info.CustomPayload == "" {
+		info.CustomPayload, err = createCustomPayload(resp.WalletAirdrop.Proof)
+		if err != nil {
+			return err
+		}
+	}
+	return writeJSON(filepath.Join(dir, "wallet", owner+".json"), info)
+}
+
+func writeJSON(filename string, value interface{ MarshalJSON() ([]byte, error) }) error {
+	content, err := value.MarshalJSON()
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filename, content, 0644)
+}
@@ -0,0 +1,77 @@
+package api
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tonkeeper/tongo/tlb"
+	"github.com/tonkeeper/tongo/ton"
+	"go.uber.org/zap"
+
+	"github.com/tonkeeper/claim-api-go/pkg/api/oas"
+	"github.com/tonkeeper/claim-api-go/pkg/prover"
+)
+
+func TestHandler_Export(t *testing.T) {
+	airdrops := map[ton.AccountID]prover.AirdropData{}
+	for i := byte(1); i <= 5; i++ {
+		owner := ton.AccountID{Workchain: 0, Address: [32]byte{i * 0x30, i}}
+		// the claim window is open, so the API includes a custom payload as the export always does.
+		airdrops[owner] = prover.AirdropData{Amount: tlb.Coins(uint64(i) * 1000), StartFrom: 1700000000, ExpireAt: 1 << 40}
+	}
+	filename := filepath.Join(t.TempDir(), "airdropData.boc")
+	writeAirdropDictionary(t, filename, airdrops)
+
+	h, jettonMaster := newSnapshotHandler(t)
+	c, err := newCampaign(zap.NewNop(), CampaignConfig{AirdropFilename: filename, JettonMaster: jettonMaster}, 1)
+	require.Nil(t, err)
+	h.campaigns[jettonMaster] = c
+	// claim statuses need jetton wallets, which aren't part of a snapshot, so the API omits them as the export does.
+	h.jettonWalletExecutor = h.accountExecutor
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.prover.Run(ctx)
+
+	dir := t.TempDir()
+	require.Nil(t, h.Export(ctx, jettonMaster, ExportOptions{Dir: dir, PageSize: 2, Concurrency: 3}))
+
+	wallets, err := os.ReadDir(filepath.Join(dir, "wallet"))
+	require.Nil(t, err)
+	require.Len(t, wallets, len(airdrops))
+	for owner := range airdrops {
+		content, err := os.ReadFile(filepath.Join(dir, "wallet", owner.ToRaw()+".json"))
+		require.Nil(t, err)
+		info, err := h.GetCampaignWalletInfo(ctx, oas.GetCampaignWalletInfoParams{JettonMaster: jettonMaster.ToRaw(), Address: owner.ToRaw()})
+		require.Nil(t, err)
+		require.NotEmpty(t, info.CustomPayload)
+		expected, err := info.MarshalJSON()
+		require.Nil(t, err)
+		require.JSONEq(t, string(expected), string(content))
+	}
+
+	pages, err := os.ReadDir(filepath.Join(dir, "wallets"))
+	require.Nil(t, err)
+	require.Len(t, pages, 3)
+	from := ton.AccountID{}.ToRaw()
+	for from != "" {
+		content, err := os.ReadFile(filepath.Join(dir, "wallets", from+".json"))
+		require.Nil(t, err)
+		page, err := h.GetCampaignWallets(ctx, oas.GetCampaignWalletsParams{JettonMaster: jettonMaster.ToRaw(), NextFrom: from, Count: 2})
+		require.Nil(t, err)
+		expected, err := page.MarshalJSON()
+		require.Nil(t, err)
+		require.JSONEq(t, string(expected), string(content))
+		from = page.NextFrom
+	}
+
+	// a prover that isn't running never takes the request, the export gives up with its context.
+	stopped, err := newCampaign(zap.NewNop(), CampaignConfig{AirdropFilename: filename, JettonMaster: jettonMaster}, 1)
+	require.Nil(t, err)
+	canceled, cancelExport := context.WithCancel(ctx)
+	cancelExport()
+	owner := ton.AccountID{Workchain: 0, Address: [32]byte{0x30, 1}}
+	require.ErrorIs(t, h.exportWallet(canceled, stopped, owner.ToRaw(), dir), context.Canceled)
+}
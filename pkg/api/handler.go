@@ -2,23 +2,23 @@ package api
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strconv"
 	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
-	"github.com/tonkeeper/claim-api-go/pkg/utils"
 	"github.com/tonkeeper/tongo/tvm"
 
-	"github.com/avast/retry-go"
 	"github.com/tonkeeper/tongo"
 	"github.com/tonkeeper/tongo/abi"
 	boc "github.com/tonkeeper/tongo/boc"
-	"github.com/tonkeeper/tongo/contract/jetton"
-	"github.com/tonkeeper/tongo/liteapi"
 	"github.com/tonkeeper/tongo/tlb"
 	"github.com/tonkeeper/tongo/ton"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 	"go.uber.org/zap"
 
 	"github.com/tonkeeper/claim-api-go/pkg/api/oas"
@@ -30,16 +30,54 @@ import (
 type Handler struct {
 	logger *zap.Logger
 
-	prover       *prover.Prover
-	jettonMaster ton.AccountID
-	cli          *liteapi.Client
-	config       string
+	// campaigns maps a jetton master to its airdrop campaign.
+	campaigns map[ton.AccountID]*campaign
+	// defaultCampaign serves the paths without a jetton master prefix.
+	defaultCampaign *campaign
 
-	proofsCache      utils.Cache[ton.AccountID, prover.WalletAirdrop]
-	keyNotFoundCache utils.Cache[ton.AccountID, struct{}]
+	merkleRootCheckInterval time.Duration
+	stateRefreshInterval    time.Duration
+
+	// backend is nil when the handler runs from a snapshot.
+	backend Backend
+	// libraries resolves library cells for emulators.
+	libraries libraryResolver
+	// config is a blockchain config for emulators, emulatorConfig is the same config parsed by libemulator.
+	config         string
+	emulatorConfig *tvm.Config
 
 	mu                     sync.RWMutex
 	jettonMasterStateCache map[ton.AccountID][2]string
+	// emulatorPools keeps ready emulators of jetton masters.
+	emulatorPools    map[ton.AccountID]*emulatorPool
+	emulatorPoolSize int
+	// queueWaitBudget is how long a request waits for a place in a full prover queue.
+	queueWaitBudget time.Duration
+	// requestTimeout limits the time of an API request, zero means no limit.
+	requestTimeout time.Duration
+	// readinessQueueThreshold is a prover queue length that makes the handler not ready.
+	readinessQueueThreshold int
+	// emulatorCheck keeps the last result of the emulator readiness check.
+	emulatorCheck cachedCheck
+	// stateInitFlights and jettonWalletFlights coalesce concurrent emulator runs for the same jetton wallet.
+	stateInitFlights    flightGroup[walletKey, string]
+	jettonWalletFlights flightGroup[walletKey, ton.AccountID]
+	// walletTemplates keeps templates to derive jetton wallets natively,
+	// nil means that a jetton master is served by the emulator.
+	walletTemplates map[ton.AccountID]*walletTemplate
+	// walletTemplateRetries keeps the time after which a failed template resolution is retried.
+	walletTemplateRetries map[ton.AccountID]time.Time
+	// walletTemplateFlights coalesces concurrent template resolutions of the same jetton master.
+	walletTemplateFlights flightGroup[ton.AccountID, *walletTemplate]
+	// walletDerivations counts native derivations to pick the ones checked against the emulator.
+	walletDerivations atomic.Uint64
+
+	// jettonWalletExecutor returns an executor with the current state of a jetton wallet.
+	jettonWalletExecutor func(ctx context.Context, jettonWallet ton.AccountID) (abi.Executor, error)
+
+	// cancel stops the background work started by Run, provers tracks running provers.
+	cancel  context.CancelFunc
+	provers sync.WaitGroup
 }
 
 func (h *Handler) GetApiInfo(ctx context.Context) (oas.GetApiInfoOK, error) {
@@ -54,40 +92,97 @@ https://github.com/ton-blockchain/TEPs/pull/180/files
 }
 
 type Config struct {
-	AirdropFilename string
-	JettonMaster    ton.AccountID
+	// Campaigns is a list of airdrop campaigns served by a handler.
+	// The first campaign is also served by the paths without a jetton master prefix.
+	Campaigns []CampaignConfig
+	// MerkleRootCheckInterval defines how often merkle roots of the campaigns are compared
+	// with the ones stored in their jetton masters. Zero disables periodic checks,
+	// but a campaign whose merkle root couldn't be verified at start is still retried.
+	MerkleRootCheckInterval time.Duration
+	// ProverWorkers is a number of goroutines generating proofs for each campaign.
+	// Zero means the number of CPUs.
+	ProverWorkers int
+	// StateRefreshInterval defines how often states of jetton masters and the blockchain config are refreshed.
+	// Zero disables refreshing.
+	StateRefreshInterval time.Duration
+	// EmulatorPoolSize is a maximum number of emulators of each jetton master.
+	// Zero means the number of CPUs.
+	EmulatorPoolSize int
+	// SnapshotFilename is a snapshot created by CreateSnapshot.
+	// If set, the handler never connects to the blockchain and serves the pinned state from the snapshot.
+	SnapshotFilename string
+	// QueueWaitBudget is how long a request waits for a place in a full prover queue before it gets 503.
+	// Zero means that a request waits for the queue as long as RequestTimeout allows.
+	QueueWaitBudget time.Duration
+	// RequestTimeout limits the time of an API request, a request running out of time gets 504.
+	// Zero means no limit.
+	RequestTimeout time.Duration
+	// ReadinessQueueThreshold is a length of a prover queue at which /readyz reports that the service is not ready.
+	// Zero disables the check.
+	ReadinessQueueThreshold int
+	// Backend configures a source of the blockchain state, it is ignored if SnapshotFilename is set.
+	Backend BackendConfig
 }
 
 var _ oas.Handler = (*Handler)(nil)
 
 func NewHandler(logger *zap.Logger, config Config) (*Handler, error) {
-	cli, err := liteapi.NewClient(liteapi.FromEnvs())
-	if err != nil {
-		return nil, err
+	if len(config.Campaigns) == 0 {
+		return nil, fmt.Errorf("no campaigns configured")
 	}
-	jettonMaster := jetton.New(config.JettonMaster, cli)
-	_ = jettonMaster
-	blockchainConfig, err := getConfig(context.Background(), cli)
-	if err != nil {
-		return nil, err
+	campaigns := make(map[ton.AccountID]*campaign, len(config.Campaigns))
+	for _, campaignConfig := range config.Campaigns {
+		if _, ok := campaigns[campaignConfig.JettonMaster]; ok {
+			return nil, fmt.Errorf("duplicate campaign for jetton master %v", campaignConfig.JettonMaster.ToRaw())
+		}
+		c, err := newCampaign(logger, campaignConfig, config.ProverWorkers)
+		if err != nil {
+			return nil, err
+		}
+		campaigns[campaignConfig.JettonMaster] = c
+	}
+	h := &Handler{
+		campaigns:               campaigns,
+		defaultCampaign:         campaigns[config.Campaigns[0].JettonMaster],
+		merkleRootCheckInterval: config.MerkleRootCheckInterval,
+		stateRefreshInterval:    config.StateRefreshInterval,
+		logger:                  logger,
+		jettonMasterStateCache:  map[ton.AccountID][2]string{},
+		emulatorPoolSize:        config.EmulatorPoolSize,
+		queueWaitBudget:         config.QueueWaitBudget,
+		requestTimeout:          config.RequestTimeout,
+		readinessQueueThreshold: config.ReadinessQueueThreshold,
+		stateInitFlights:        flightGroup[walletKey, string]{name: "stateInit"},
+		jettonWalletFlights:     flightGroup[walletKey, ton.AccountID]{name: "jettonWallet"},
+		walletTemplateFlights:   flightGroup[ton.AccountID, *walletTemplate]{name: "walletTemplate"},
+		walletTemplates:         map[ton.AccountID]*walletTemplate{},
+	}
+	if config.SnapshotFilename != "" {
+		if err := h.loadSnapshot(config.SnapshotFilename); err != nil {
+			return nil, err
+		}
+	} else {
+		backend, err := NewBackend(logger, config.Backend)
+		if err != nil {
+			return nil, err
+		}
+		blockchainConfig, err := getConfig(context.Background(), backend)
+		if err != nil {
+			return nil, err
+		}
+		h.backend = backend
+		h.libraries = backend
+		if err := h.setConfig(blockchainConfig); err != nil {
+			return nil, err
+		}
 	}
-	proverConfig := prover.Config{
-		Filename: config.AirdropFilename,
+	h.jettonWalletExecutor = h.accountExecutor
+	// every proof would fail on-chain if we serve a dictionary that doesn't match the jetton master,
+	// so the handler isn't ready until merkle roots match, see runMerkleRootVerification.
+	if err := h.verifyMerkleRoots(context.Background()); err != nil {
+		logger.Error("merkle root verification failed", zap.Error(err))
 	}
-	p, err := prover.NewProver(logger, proverConfig)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create prover: %w", err)
-	}
-	return &Handler{
-		prover:                 p,
-		cli:                    cli,
-		logger:                 logger,
-		jettonMaster:           config.JettonMaster,
-		jettonMasterStateCache: map[ton.AccountID][2]string{},
-		config:                 blockchainConfig,
-		proofsCache:            utils.NewLRUCache[ton.AccountID, prover.WalletAirdrop](700_000, "proofs"),
-		keyNotFoundCache:       utils.NewLRUCache[ton.AccountID, struct{}](700_000, "keyNotFound"),
-	}, nil
+	return h, nil
 }
 
 func (h *Handler) NewError(ctx context.Context, err error) *oas.ErrorStatusCode {
@@ -99,11 +194,72 @@ func (h *Handler) NewError(ctx context.Context, err error) *oas.ErrorStatusCode
 	}
 }
 
+// Run starts provers and background checks, they are stopped by Shutdown or once the context is canceled.
 func (h *Handler) Run(ctx context.Context) {
-	go h.prover.Run(ctx)
+	ctx, h.cancel = context.WithCancel(ctx)
+	for _, c := range h.campaigns {
+		h.provers.Add(1)
+		go func(c *campaign) {
+			defer h.provers.Done()
+			c.prover.Run(ctx)
+		}(c)
+	}
+	go h.runMerkleRootVerification(ctx, h.merkleRootCheckInterval)
+	go h.runStatsMetrics(ctx)
+	if runner, ok := h.backend.(backendRunner); ok {
+		go runner.Run(ctx)
+	}
+	// a snapshot is never refreshed.
+	if h.stateRefreshInterval > 0 && h.backend != nil {
+		go h.runStateRefresher(ctx, h.stateRefreshInterval)
+	}
+}
+
+// Shutdown waits until provers process all queued requests and stops the background work started by Run.
+// Requests left in the queues once the context is done are dropped.
+func (h *Handler) Shutdown(ctx context.Context) error {
+	if h.cancel == nil {
+		// nothing is running.
+		return nil
+	}
+	var errs []error
+	for _, c := range h.campaigns {
+		if err := c.prover.Drain(ctx); err != nil {
+			errs = append(errs, fmt.Errorf("failed to drain queue of campaign %v: %w", c.jettonMaster.ToRaw(), err))
+		}
+	}
+	h.cancel()
+	h.provers.Wait()
+	return errors.Join(errs...)
+}
+
+// Reload reloads airdrop dictionaries of all campaigns.
+// Cached results are dropped only for accounts whose airdrop data has changed,
+// proofs of the other cached accounts are regenerated against the new dictionaries.
+func (h *Handler) Reload() error {
+	var errs []error
+	for _, c := range h.campaigns {
+		// every proof would fail on-chain if a new dictionary doesn't match the jetton master.
+		validated := false
+		validate := func(merkleRoot tlb.Bits256) error {
+			err := h.matchMerkleRoot(context.Background(), c.jettonMaster, merkleRoot)
+			validated = err == nil
+			return err
+		}
+		if err := c.reload(h.logger, validate); err != nil {
+			errs = append(errs, fmt.Errorf("failed to reload campaign %v: %w", c.jettonMaster.ToRaw(), err))
+			continue
+		}
+		if validated {
+			c.setMerkleRootStatus(merkleRootMatch)
+		}
+	}
+	return errors.Join(errs...)
 }
 
-func (h *Handler) convertToWalletInfo(ctx context.Context, airdrop prover.WalletAirdrop) (*oas.WalletInfo, error) {
+// convertToWalletInfo builds a response for the given airdrop.
+// Getting a claim status takes a request to the backend, so it is optional.
+func (h *Handler) convertToWalletInfo(ctx context.Context, c *campaign, airdrop prover.WalletAirdrop, withClaimStatus bool) (*oas.WalletInfo, error) {
 	var err error
 	var customPayload string
 
@@ -114,11 +270,11 @@ func (h *Handler) convertToWalletInfo(ctx context.Context, airdrop prover.Wallet
 			return nil, err
 		}
 	}
-	stateInit, err := h.getStateInit(ctx, airdrop.AccountID)
+	stateInit, err := h.getStateInit(ctx, c.jettonMaster, airdrop.AccountID)
 	if err != nil {
 		return nil, err
 	}
-	jettonWallet, err := h.getJettonWallet(ctx, airdrop.AccountID)
+	jettonWallet, err := h.getJettonWallet(ctx, c.jettonMaster, airdrop.AccountID)
 	if err != nil {
 		return nil, err
 	}
@@ -127,55 +283,98 @@ func (h *Handler) convertToWalletInfo(ctx context.Context, airdrop prover.Wallet
 		StartFrom: strconv.FormatUint(uint64(airdrop.Data.StartFrom), 10),
 		ExpiredAt: strconv.FormatUint(uint64(airdrop.Data.ExpireAt), 10),
 	}
-	return &oas.WalletInfo{
+	info := &oas.WalletInfo{
 		Owner:          airdrop.AccountID.ToRaw(),
 		JettonWallet:   jettonWallet.ToRaw(),
 		CustomPayload:  customPayload,
 		StateInit:      oas.NewOptString(stateInit),
 		CompressedInfo: oas.NewOptWalletInfoCompressedInfo(compressedInfo),
-	}, nil
+	}
+	if withClaimStatus {
+		// the status is informational, so it is omitted instead of failing the whole request.
+		status, err := h.claimStatus(ctx, c, airdrop, jettonWallet)
+		switch {
+		case errors.Is(err, errOffline):
+			// jetton wallets aren't part of a snapshot.
+		case err != nil:
+			h.logger.Warn("failed to get claim status", zap.String("owner", airdrop.AccountID.ToRaw()), zap.Error(err))
+		default:
+			info.ClaimStatus = oas.NewOptWalletInfoClaimStatus(status)
+		}
+	}
+	return info, nil
 }
 
 func (h *Handler) GetWalletInfo(ctx context.Context, params oas.GetWalletInfoParams) (*oas.WalletInfo, error) {
-	accountID, err := ton.ParseAccountID(params.Address)
+	return h.walletInfo(ctx, h.defaultCampaign, params.Address)
+}
+
+func (h *Handler) GetCampaignWalletInfo(ctx context.Context, params oas.GetCampaignWalletInfoParams) (*oas.WalletInfo, error) {
+	c, err := h.campaign(params.JettonMaster)
+	if err != nil {
+		return nil, err
+	}
+	return h.walletInfo(ctx, c, params.Address)
+}
+
+func (h *Handler) walletInfo(ctx context.Context, c *campaign, address string) (*oas.WalletInfo, error) {
+	accountID, err := ton.ParseAccountID(address)
 	if err != nil {
 		return nil, BadRequest("failed to parse account id")
 	}
 
-	if proof, ok := h.proofsCache.Get(accountID); ok {
-		info, err := h.convertToWalletInfo(ctx, proof)
-		if err != nil {
-			return nil, InternalError(err)
+	proof, ok, err := c.cachedProof(accountID)
+	if err != nil {
+		return nil, err
+	}
+	if !ok {
+		if proof, err = h.prove(ctx, c, accountID); err != nil {
+			return nil, err
 		}
-		return info, nil
 	}
-	if _, ok := h.keyNotFoundCache.Get(accountID); ok {
-		return nil, NotFound("account not found")
+	info, err := h.convertToWalletInfo(ctx, c, proof, true)
+	if err != nil && ctx.Err() != nil {
+		return nil, requestTimeout("emulator")
 	}
-
-	responseCh := make(chan prover.ProofResponse, 1)
-	h.prover.Queue() <- prover.ProofRequest{
-		AccountID:  accountID,
-		ResponseCh: responseCh,
+	if err != nil {
+		return nil, InternalError(err)
 	}
-	select {
-	case <-ctx.Done():
-		return nil, BadRequest("timeout")
-	case resp := <-responseCh:
-		if resp.Err != nil && strings.Contains(resp.Err.Error(), "key is not found") {
-			h.keyNotFoundCache.Set(accountID, struct{}{})
-			return nil, NotFound("account not found")
-		}
-		if resp.Err != nil {
-			return nil, InternalError(resp.Err)
+	return info, nil
+}
+
+// prove requests a proof of the given account from the prover of the campaign.
+// Concurrent requests of the same account share a single request to the prover.
+func (h *Handler) prove(ctx context.Context, c *campaign, accountID ton.AccountID) (_ prover.WalletAirdrop, err error) {
+	ctx, span := tracer.Start(ctx, "prove", trace.WithAttributes(attribute.String("account", accountID.ToRaw())))
+	defer func() { endSpan(span, err) }()
+
+	proof, err := c.proofFlights.do(ctx, accountID, func(ctx context.Context) (prover.WalletAirdrop, error) {
+		if h.requestTimeout > 0 {
+			// a shared request doesn't inherit deadlines of callers, so the prover queue needs its own one.
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, h.requestTimeout)
+			defer cancel()
 		}
-		h.proofsCache.Set(accountID, resp.WalletAirdrop, utils.WithExpiration(7*time.Minute))
-		info, err := h.convertToWalletInfo(ctx, resp.WalletAirdrop)
+		responseCh := make(chan prover.ProofResponse, 1)
+		err := h.enqueue(ctx, c, c.prover.Queue(), prover.ProofRequest{
+			Ctx:        ctx,
+			AccountID:  accountID,
+			ResponseCh: responseCh,
+		})
 		if err != nil {
-			return nil, InternalError(err)
+			return prover.WalletAirdrop{}, err
 		}
-		return info, nil
+		select {
+		case <-ctx.Done():
+			return prover.WalletAirdrop{}, ctx.Err()
+		case resp := <-responseCh:
+			return c.handleProofResponse(accountID, resp)
+		}
+	})
+	if err != nil && ctx.Err() != nil {
+		return prover.WalletAirdrop{}, requestTimeout("prover")
 	}
+	return proof, err
 }
 
 func createCustomPayload(proof []byte) (string, error) {
@@ -197,19 +396,38 @@ func createCustomPayload(proof []byte) (string, error) {
 }
 
 func (h *Handler) GetWallets(ctx context.Context, params oas.GetWalletsParams) (*oas.WalletList, error) {
-	next, err := ton.ParseAccountID(params.NextFrom)
+	return h.wallets(ctx, h.defaultCampaign, params.NextFrom, params.Count)
+}
+
+func (h *Handler) GetCampaignWallets(ctx context.Context, params oas.GetCampaignWalletsParams) (*oas.WalletList, error) {
+	c, err := h.campaign(params.JettonMaster)
+	if err != nil {
+		return nil, err
+	}
+	return h.wallets(ctx, c, params.NextFrom, params.Count)
+}
+
+func (h *Handler) wallets(ctx context.Context, c *campaign, from string, count int) (*oas.WalletList, error) {
+	next, err := ton.ParseAccountID(from)
 	if err != nil {
 		return nil, BadRequest("failed to parse next from")
 	}
 	ch := make(chan prover.EnumerateResponse, 1)
-	h.prover.Queue() <- prover.EnumerateRequest{
+	err = h.enqueue(ctx, c, c.prover.EnumerationQueue(), prover.EnumerateRequest{
+		Ctx:        ctx,
 		NextFrom:   next,
-		Count:      params.Count,
+		Count:      count,
 		ResponseCh: ch,
+	})
+	if err != nil && ctx.Err() != nil {
+		return nil, requestTimeout("queue")
+	}
+	if err != nil {
+		return nil, err
 	}
 	select {
 	case <-ctx.Done():
-		return nil, BadRequest("timeout")
+		return nil, requestTimeout("prover")
 	case resp := <-ch:
 		if resp.Err != nil && strings.Contains(resp.Err.Error(), "key is not found") {
 			return nil, NotFound("account not not found")
@@ -237,31 +455,42 @@ func (h *Handler) GetWallets(ctx context.Context, params oas.GetWalletsParams) (
 	}
 }
 
-func (h *Handler) getStateInit(ctx context.Context, owner ton.AccountID) (string, error) {
-	// TODO: add cache
-	var stateInit boc.Cell
-	err := retry.Do(func() error {
-		ctx, cancel := context.WithTimeout(ctx, 1*time.Second)
-		defer cancel()
-		executor, err := h.executor(ctx, h.jettonMaster)
-		if err != nil {
-			return err
-		}
-		_, value, err := GetWalletStateInitAndSalt(ctx, executor, h.jettonMaster, owner.ToMsgAddress())
+func (h *Handler) getStateInit(ctx context.Context, jettonMaster ton.AccountID, owner ton.AccountID) (_ string, err error) {
+	ctx, span := tracer.Start(ctx, "getStateInit", trace.WithAttributes(attribute.String("owner", owner.ToRaw())))
+	defer func() { endSpan(span, err) }()
+
+	if wallet, ok := h.deriveJettonWallet(ctx, jettonMaster, owner); ok {
+		return wallet.stateInit.ToBocBase64()
+	}
+	return h.stateInitFlights.do(ctx, walletKey{jettonMaster, owner}, func(ctx context.Context) (string, error) {
+		stateInit, err := h.emulateStateInit(ctx, jettonMaster, owner)
 		if err != nil {
-			return err
+			return "", err
 		}
-		result, ok := value.(GetWalletStateInitAndSaltResult)
-		if !ok {
-			return fmt.Errorf("failed to get state init")
-		}
-		stateInit = boc.Cell(result.StateInit)
-		return nil
-	}, retry.Attempts(3), retry.Delay(1*time.Second))
+		return stateInit.ToBocBase64()
+	})
+}
+
+func (h *Handler) emulateStateInit(ctx context.Context, jettonMaster ton.AccountID, owner ton.AccountID) (*boc.Cell, error) {
+	// failures of the backend are handled by the backend itself, see LiteserverPool.
+	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
+	defer cancel()
+
+	executor, release, err := h.executor(ctx, jettonMaster)
 	if err != nil {
-		return "", err
+		return nil, err
 	}
-	return stateInit.ToBocBase64()
+	defer release()
+	_, value, err := GetWalletStateInitAndSalt(ctx, executor, jettonMaster, owner.ToMsgAddress())
+	if err != nil {
+		return nil, err
+	}
+	result, ok := value.(GetWalletStateInitAndSaltResult)
+	if !ok {
+		return nil, fmt.Errorf("failed to get state init")
+	}
+	stateInit := boc.Cell(result.StateInit)
+	return &stateInit, nil
 }
 
 type GetWalletStateInitAndSaltResult struct {
@@ -307,16 +536,28 @@ func DecodeGetWalletStateInitAndSaltResult(stack tlb.VmStack) (resultType string
 	return "GetWalletStateInitAndSaltResult", result, err
 }
 
-func (h *Handler) getJettonWallet(ctx context.Context, owner ton.AccountID) (ton.AccountID, error) {
+func (h *Handler) getJettonWallet(ctx context.Context, jettonMaster ton.AccountID, owner ton.AccountID) (_ ton.AccountID, err error) {
+	ctx, span := tracer.Start(ctx, "getJettonWallet", trace.WithAttributes(attribute.String("owner", owner.ToRaw())))
+	defer func() { endSpan(span, err) }()
+
+	if wallet, ok := h.deriveJettonWallet(ctx, jettonMaster, owner); ok {
+		return wallet.address, nil
+	}
+	return h.jettonWalletFlights.do(ctx, walletKey{jettonMaster, owner}, func(ctx context.Context) (ton.AccountID, error) {
+		return h.emulateJettonWallet(ctx, jettonMaster, owner)
+	})
+}
+
+func (h *Handler) emulateJettonWallet(ctx context.Context, jettonMaster ton.AccountID, owner ton.AccountID) (ton.AccountID, error) {
 	ctx, cancel := context.WithTimeout(ctx, 2*time.Second)
 	defer cancel()
 
-	// TODO: add cache
-	executor, err := h.executor(ctx, h.jettonMaster)
+	executor, release, err := h.executor(ctx, jettonMaster)
 	if err != nil {
 		return ton.AccountID{}, err
 	}
-	_, result, err := abi.GetWalletAddress(ctx, executor, h.jettonMaster, owner.ToMsgAddress())
+	defer release()
+	_, result, err := abi.GetWalletAddress(ctx, executor, jettonMaster, owner.ToMsgAddress())
 	if err != nil {
 		return ton.AccountID{}, err
 	}
@@ -334,31 +575,8 @@ func (h *Handler) getJettonWallet(ctx context.Context, owner ton.AccountID) (ton
 	return *jettonWalletAccountID, nil
 }
 
-func (h *Handler) executor(ctx context.Context, id ton.AccountID) (abi.Executor, error) {
-	state, ok := h.jettonMasterState(id)
-	if !ok {
-		account, err := h.cli.GetAccountState(ctx, id)
-		if err != nil {
-			return nil, err
-		}
-		data := account.Account.Account.Storage.State.AccountActive.StateInit.Data.Value.Value
-		code := account.Account.Account.Storage.State.AccountActive.StateInit.Code.Value.Value
-		d, err := data.ToBocBase64()
-		if err != nil {
-			return nil, err
-		}
-		c, err := code.ToBocBase64()
-		if err != nil {
-			return nil, err
-		}
-		state = [2]string{c, d}
-		h.setJettonMasterState(id, state)
-	}
-	return tvm.NewEmulatorFromBOCsBase64(state[0], state[1], h.config, tvm.WithLibraryResolver(h.cli))
-}
-
-func getConfig(ctx context.Context, client *liteapi.Client) (string, error) {
-	config, err := client.GetConfigAll(ctx, 0)
+func getConfig(ctx context.Context, backend Backend) (string, error) {
+	config, err := backend.GetConfigAll(ctx, 0)
 	if err != nil {
 		return "", err
 	}
@@ -387,9 +605,29 @@ func getConfig(ctx context.Context, client *liteapi.Client) (string, error) {
 func (h *Handler) setJettonMasterState(accountID ton.AccountID, state [2]string) {
 	h.mu.Lock()
 	defer h.mu.Unlock()
+	if h.jettonMasterStateCache[accountID] != state {
+		// the wallet code and merkle root are stored in the jetton master, so templates are stale too.
+		delete(h.emulatorPools, accountID)
+		delete(h.walletTemplates, accountID)
+		delete(h.walletTemplateRetries, accountID)
+	}
 	h.jettonMasterStateCache[accountID] = state
 }
 
+// setConfig replaces the blockchain config used by emulators.
+func (h *Handler) setConfig(config string) error {
+	emulatorConfig, err := tvm.CreateConfig(config)
+	if err != nil {
+		return err
+	}
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.config = config
+	h.emulatorConfig = emulatorConfig
+	h.emulatorPools = map[ton.AccountID]*emulatorPool{}
+	return nil
+}
+
 func (h *Handler) jettonMasterState(accountID ton.AccountID) (state [2]string, ok bool) {
 	h.mu.RLock()
 	defer h.mu.RUnlock()
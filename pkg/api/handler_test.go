@@ -1,16 +1,23 @@
 package api
 
 import (
+	"bytes"
 	"context"
-	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus"
 	"github.com/stretchr/testify/require"
 	"github.com/tonkeeper/tongo/boc"
-	"github.com/tonkeeper/tongo/liteapi"
 	"github.com/tonkeeper/tongo/tlb"
 	"github.com/tonkeeper/tongo/ton"
 	"go.uber.org/zap"
+
+	"github.com/tonkeeper/claim-api-go/pkg/api/oas"
+	"github.com/tonkeeper/claim-api-go/pkg/prover"
 )
 
 func TestHandler_getStateInit(t *testing.T) {
@@ -25,15 +32,8 @@ func TestHandler_getStateInit(t *testing.T) {
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			logger, _ := zap.NewDevelopment()
-			cli, err := liteapi.NewClient(liteapi.Mainnet())
-			require.Nil(t, err)
-			h := &Handler{
-				cli:          cli,
-				logger:       logger,
-				jettonMaster: ton.MustParseAccountID("EQD6Z9DHc5Mx-8PI8I4BjGX0d2NhapaRAK12CgstweNoMint"),
-			}
-			stateInit, err := h.getStateInit(context.Background(), tt.owner)
+			h, jettonMaster := newSnapshotHandler(t)
+			stateInit, err := h.getStateInit(context.Background(), jettonMaster, tt.owner)
 			require.Nil(t, err)
 			cells, err := boc.DeserializeBocBase64(stateInit)
 			require.Nil(t, err)
@@ -41,7 +41,254 @@ func TestHandler_getStateInit(t *testing.T) {
 
 			var init tlb.StateInit
 			require.Nil(t, tlb.Unmarshal(cells[0], &init))
-			fmt.Printf("stateInit: %v\n", stateInit)
 		})
 	}
 }
+
+func TestHandler_GetWalletInfo(t *testing.T) {
+	jettonMaster := ton.MustParseAccountID("EQD6Z9DHc5Mx-8PI8I4BjGX0d2NhapaRAK12CgstweNoMint")
+	c, err := newCampaign(zap.NewNop(), CampaignConfig{
+		AirdropFilename: "../prover/testdata/airdropData.boc",
+		JettonMaster:    jettonMaster,
+	}, 2)
+	require.Nil(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.prover.Run(ctx)
+
+	backend := NewMemoryBackend()
+	template := &walletTemplate{layout: mintlessLayout{}, code: boc.NewCell(), merkleRoot: c.prover.MerkleRoot()}
+	h := &Handler{
+		logger:                 zap.NewNop(),
+		campaigns:              map[ton.AccountID]*campaign{jettonMaster: c},
+		defaultCampaign:        c,
+		backend:                backend,
+		libraries:              backend,
+		jettonMasterStateCache: map[ton.AccountID][2]string{},
+		walletTemplates:        map[ton.AccountID]*walletTemplate{jettonMaster: template},
+	}
+	h.jettonWalletExecutor = h.accountExecutor
+
+	owner := ton.MustParseAccountID("0:050b89727f74efd71e3f5c396c76c6df7ee71aced7c2ec7a8c55bb8bba8d1399")
+	wallet, err := template.derive(owner, jettonMaster)
+	require.Nil(t, err)
+	stateInit, err := wallet.stateInit.ToBocBase64()
+	require.Nil(t, err)
+
+	info, err := h.GetWalletInfo(ctx, oas.GetWalletInfoParams{Address: owner.ToRaw()})
+	require.Nil(t, err)
+	require.Equal(t, owner.ToRaw(), info.Owner)
+	require.Equal(t, wallet.address.ToRaw(), info.JettonWallet)
+	require.Equal(t, stateInit, info.StateInit.Value)
+	// the jetton wallet isn't deployed and the test airdrop ended in 2024.
+	require.Equal(t, oas.NewOptWalletInfoClaimStatus(oas.WalletInfoClaimStatusExpired), info.ClaimStatus)
+
+	_, err = h.GetWalletInfo(ctx, oas.GetWalletInfoParams{Address: "0:ff41b315c634b4ea4814b9262499567d36e9c7b13da09476f11a41d94e2cb700"})
+	require.NotNil(t, err)
+}
+
+func TestHandler_GetCampaignWalletInfo(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	owner := ton.MustParseAccountID("0:050b89727f74efd71e3f5c396c76c6df7ee71aced7c2ec7a8c55bb8bba8d1399")
+	first := ton.MustParseAccountID("EQD6Z9DHc5Mx-8PI8I4BjGX0d2NhapaRAK12CgstweNoMint")
+	second := ton.MustParseAccountID("0:6ccd325a858c379693fae2bcaab1c2906831a4e10a6c3bb44ee8b615bca1d220")
+	filename := filepath.Join(t.TempDir(), "second.boc")
+	writeAirdropDictionary(t, filename, map[ton.AccountID]prover.AirdropData{
+		owner: {Amount: 1000, StartFrom: 1, ExpireAt: 2},
+	})
+
+	backend := NewMemoryBackend()
+	h := &Handler{
+		logger:                 zap.NewNop(),
+		campaigns:              map[ton.AccountID]*campaign{},
+		backend:                backend,
+		libraries:              backend,
+		jettonMasterStateCache: map[ton.AccountID][2]string{},
+		walletTemplates:        map[ton.AccountID]*walletTemplate{},
+	}
+	h.jettonWalletExecutor = h.accountExecutor
+	for jettonMaster, airdropFilename := range map[ton.AccountID]string{first: "../prover/testdata/airdropData.boc", second: filename} {
+		c, err := newCampaign(zap.NewNop(), CampaignConfig{AirdropFilename: airdropFilename, JettonMaster: jettonMaster}, 1)
+		require.Nil(t, err)
+		go c.prover.Run(ctx)
+		h.campaigns[jettonMaster] = c
+		h.walletTemplates[jettonMaster] = &walletTemplate{layout: mintlessLayout{}, code: boc.NewCell(), merkleRoot: c.prover.MerkleRoot()}
+	}
+
+	// other tests serve the same jetton master, so the metric is compared with its previous value.
+	hits := map[ton.AccountID]float64{}
+	for _, jettonMaster := range []ton.AccountID{first, second} {
+		hits[jettonMaster] = cacheMetricValue(t, cacheMetricName("proofs", jettonMaster), "hit")
+	}
+	amounts := map[ton.AccountID]string{}
+	for _, jettonMaster := range []ton.AccountID{first, second} {
+		// the second request is served from the cache of the campaign.
+		for i := 0; i < 2; i++ {
+			info, err := h.GetCampaignWalletInfo(ctx, oas.GetCampaignWalletInfoParams{JettonMaster: jettonMaster.ToRaw(), Address: owner.ToRaw()})
+			require.Nil(t, err)
+			amounts[jettonMaster] = info.CompressedInfo.Value.Amount
+		}
+	}
+	require.Equal(t, "1000", amounts[second])
+	require.NotEqual(t, amounts[first], amounts[second])
+
+	for _, jettonMaster := range []ton.AccountID{first, second} {
+		stats := h.campaigns[jettonMaster].proofsCache.Stats()
+		require.Equal(t, 1, stats.Len)
+		require.Equal(t, uint64(1), stats.Hits)
+		require.Equal(t, hits[jettonMaster]+1, cacheMetricValue(t, cacheMetricName("proofs", jettonMaster), "hit"))
+	}
+}
+
+// cacheMetricValue returns the value of the cache metric with the given labels.
+func cacheMetricValue(t *testing.T, name, result string) float64 {
+	families, err := prometheus.DefaultGatherer.Gather()
+	require.Nil(t, err)
+	for _, family := range families {
+		if family.GetName() != "db_cache" {
+			continue
+		}
+		for _, metric := range family.GetMetric() {
+			labels := map[string]string{}
+			for _, label := range metric.GetLabel() {
+				labels[label.GetName()] = label.GetValue()
+			}
+			if labels["name"] == name && labels["result"] == result {
+				return metric.GetGauge().GetValue()
+			}
+		}
+	}
+	return 0
+}
+
+func TestHandler_Shutdown(t *testing.T) {
+	c, err := newCampaign(zap.NewNop(), CampaignConfig{AirdropFilename: "../prover/testdata/airdropData.boc"}, 2)
+	require.Nil(t, err)
+	h := &Handler{
+		logger:          zap.NewNop(),
+		campaigns:       map[ton.AccountID]*campaign{c.jettonMaster: c},
+		defaultCampaign: c,
+	}
+	// nothing to wait for before Run.
+	require.Nil(t, h.Shutdown(context.Background()))
+
+	h.Run(context.Background())
+	owner := ton.MustParseAccountID("0:050b89727f74efd71e3f5c396c76c6df7ee71aced7c2ec7a8c55bb8bba8d1399")
+	var channels []chan prover.ProofResponse
+	for i := 0; i < 100; i++ {
+		ch := make(chan prover.ProofResponse, 1)
+		c.prover.Queue() <- prover.ProofRequest{AccountID: owner, ResponseCh: ch}
+		channels = append(channels, ch)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+	require.Nil(t, h.Shutdown(ctx))
+	// every queued request is answered before the provers stop.
+	for _, ch := range channels {
+		require.Equal(t, 1, len(ch))
+	}
+}
+
+// airdropKey is a key of an airdrop dictionary.
+type airdropKey struct {
+	tlb.MsgAddress
+}
+
+func (k airdropKey) Equal(other any) bool {
+	otherKey, ok := other.(airdropKey)
+	return ok && k.MsgAddress == otherKey.MsgAddress
+}
+
+func (k airdropKey) FixedSize() int {
+	return 267
+}
+
+func (k airdropKey) MarshalTLB(c *boc.Cell, encoder *tlb.Encoder) error {
+	return encoder.Marshal(c, k.MsgAddress)
+}
+
+// writeAirdropDictionary writes an airdrop dictionary file with the given recipients of the basechain.
+func writeAirdropDictionary(t *testing.T, filename string, airdrops map[ton.AccountID]prover.AirdropData) {
+	accountIDs := make([]ton.AccountID, 0, len(airdrops))
+	for accountID := range airdrops {
+		accountIDs = append(accountIDs, accountID)
+	}
+	// the hashmap encoder expects sorted keys.
+	sort.Slice(accountIDs, func(i, j int) bool {
+		return bytes.Compare(accountIDs[i].Address[:], accountIDs[j].Address[:]) < 0
+	})
+	keys := make([]airdropKey, 0, len(airdrops))
+	values := make([]prover.AirdropData, 0, len(airdrops))
+	for _, accountID := range accountIDs {
+		keys = append(keys, airdropKey{accountID.ToMsgAddress()})
+		values = append(values, airdrops[accountID])
+	}
+	cell := boc.NewCell()
+	require.Nil(t, tlb.Marshal(cell, tlb.NewHashmap(keys, values)))
+	content, err := cell.ToBoc()
+	require.Nil(t, err)
+	require.Nil(t, os.WriteFile(filename, content, 0644))
+}
+
+func TestHandler_Reload(t *testing.T) {
+	filename := filepath.Join(t.TempDir(), "airdropData.boc")
+	content, err := os.ReadFile("../prover/testdata/airdropData.boc")
+	require.Nil(t, err)
+	require.Nil(t, os.WriteFile(filename, content, 0644))
+
+	h, jettonMaster := newSnapshotHandler(t)
+	c, err := newCampaign(zap.NewNop(), CampaignConfig{AirdropFilename: filename, JettonMaster: jettonMaster}, 1)
+	require.Nil(t, err)
+	h.campaigns[jettonMaster] = c
+	merkleRoot := c.prover.MerkleRoot()
+
+	owner := ton.MustParseAccountID("0:050b89727f74efd71e3f5c396c76c6df7ee71aced7c2ec7a8c55bb8bba8d1399")
+	other := ton.MustParseAccountID("0:6ccd325a858c379693fae2bcaab1c2906831a4e10a6c3bb44ee8b615bca1d220")
+	writeAirdropDictionary(t, filename, map[ton.AccountID]prover.AirdropData{
+		owner: {Amount: tlb.Coins(1000), StartFrom: 1700000000, ExpireAt: 1800000000},
+		other: {Amount: tlb.Coins(2000), StartFrom: 1700000000, ExpireAt: 1800000000},
+	})
+	// the jetton master still stores the previous merkle root, so the new dictionary is rejected.
+	require.ErrorIs(t, h.Reload(), errMerkleRootMismatch)
+	require.Equal(t, merkleRoot, c.prover.MerkleRoot())
+
+	// the jetton master is updated to the new dictionary.
+	newDictionary, err := prover.NewProver(zap.NewNop(), prover.Config{Filename: filename})
+	require.Nil(t, err)
+	newRoot := newDictionary.MerkleRoot()
+	data := boc.NewCell()
+	require.Nil(t, data.WriteBytes(newRoot[:]))
+	dataBoc, err := data.ToBocBase64()
+	require.Nil(t, err)
+	state, _ := h.jettonMasterState(jettonMaster)
+	h.setJettonMasterState(jettonMaster, [2]string{state[0], dataBoc})
+
+	// a proof of the previous dictionary is cached and a request of the previous dictionary is in flight.
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.prover.Run(ctx)
+	stale, err := h.prove(ctx, c, owner)
+	require.Nil(t, err)
+	_, cached, err := c.cachedProof(owner)
+	require.Nil(t, err)
+	require.True(t, cached)
+
+	require.Nil(t, h.Reload())
+	require.Equal(t, newRoot, c.prover.MerkleRoot())
+	// the response lands after the swap, it mustn't be served for the new dictionary.
+	_, err = c.handleProofResponse(owner, prover.ProofResponse{WalletAirdrop: stale, MerkleRoot: merkleRoot})
+	require.Nil(t, err)
+	c.proofsCache.Set(owner, rootedProof{merkleRoot: merkleRoot, walletAirdrop: stale})
+	_, cached, err = c.cachedProof(owner)
+	require.Nil(t, err)
+	require.False(t, cached)
+
+	// cached accounts are proved again against the new dictionary in the background.
+	c.refreshProofs(zap.NewNop(), newRoot, []ton.AccountID{owner})
+	proof, cached, err := c.cachedProof(owner)
+	require.Nil(t, err)
+	require.True(t, cached)
+	require.Equal(t, tlb.Coins(1000), proof.Data.Amount)
+	require.NotEqual(t, stale.Proof, proof.Proof)
+}
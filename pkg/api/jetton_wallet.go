@@ -0,0 +1,305 @@
+package api
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/tonkeeper/tongo/abi"
+	"github.com/tonkeeper/tongo/boc"
+	"github.com/tonkeeper/tongo/tlb"
+	"github.com/tonkeeper/tongo/ton"
+	"go.uber.org/zap"
+)
+
+// A state init of a jetton wallet is a deterministic function of the wallet code and the owner,
+// and the wallet address is the hash of its state init.
+// So instead of running get-methods of a jetton master in the emulator for every owner,
+// we derive them natively once the layout of the jetton wallet data is known.
+//
+// Layouts are checked against the emulator with random owners once per jetton master,
+// and a sample of derived wallets is checked against the emulator while serving.
+// A jetton master with an unknown layout or a failed check is served by the emulator.
+
+// walletLayout builds a state init of a jetton wallet.
+type walletLayout interface {
+	name() string
+	stateInit(owner, jettonMaster ton.AccountID, code *boc.Cell, merkleRoot tlb.Bits256) (*boc.Cell, error)
+}
+
+// knownWalletLayouts are tried in order, the first one matching the emulator wins.
+// A layout is added only along with a fixture of a real jetton master using it.
+var knownWalletLayouts = []walletLayout{
+	mintlessLayout{},
+}
+
+var walletSpotChecksMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+	Name: "claim_api_wallet_spot_checks_total",
+	Help: "Number of natively derived jetton wallets checked against the emulator by result: match, mismatch or error",
+}, []string{"jetton_master", "result"})
+
+const (
+	// walletTemplateProbes is a number of random owners a layout must match at resolution.
+	walletTemplateProbes = 16
+)
+
+// walletSpotCheckRate is how many native derivations share one check against the emulator.
+var walletSpotCheckRate uint64 = 100
+
+// walletTemplate is everything required to derive jetton wallets of a jetton master.
+type walletTemplate struct {
+	layout     walletLayout
+	code       *boc.Cell
+	merkleRoot tlb.Bits256
+}
+
+type derivedWallet struct {
+	stateInit *boc.Cell
+	address   ton.AccountID
+}
+
+func (t *walletTemplate) derive(owner, jettonMaster ton.AccountID) (derivedWallet, error) {
+	stateInit, err := t.layout.stateInit(owner, jettonMaster, t.code, t.merkleRoot)
+	if err != nil {
+		return derivedWallet{}, err
+	}
+	hash, err := stateInit.Hash256()
+	if err != nil {
+		return derivedWallet{}, err
+	}
+	// jetton wallets are always deployed to the basechain.
+	return derivedWallet{stateInit: stateInit, address: ton.AccountID{Workchain: 0, Address: hash}}, nil
+}
+
+// mintlessWalletData is the data of a mintless jetton wallet.
+type mintlessWalletData struct {
+	Status       tlb.Uint4
+	Balance      tlb.Coins
+	Owner        tlb.MsgAddress
+	JettonMaster tlb.MsgAddress
+	MerkleRoot   tlb.Bits256
+}
+
+func buildStateInit(code *boc.Cell, data any) (*boc.Cell, error) {
+	dataCell := boc.NewCell()
+	if err := tlb.Marshal(dataCell, data); err != nil {
+		return nil, err
+	}
+	stateInit := tlb.StateInit{
+		Code: tlb.Maybe[tlb.Ref[boc.Cell]]{Exists: true, Value: tlb.Ref[boc.Cell]{Value: *code}},
+		Data: tlb.Maybe[tlb.Ref[boc.Cell]]{Exists: true, Value: tlb.Ref[boc.Cell]{Value: *dataCell}},
+	}
+	cell := boc.NewCell()
+	if err := tlb.Marshal(cell, stateInit); err != nil {
+		return nil, err
+	}
+	return cell, nil
+}
+
+// mintlessLayout is a mintless jetton wallet without shard optimization.
+type mintlessLayout struct{}
+
+func (mintlessLayout) name() string {
+	return "mintless"
+}
+
+func (mintlessLayout) stateInit(owner, jettonMaster ton.AccountID, code *boc.Cell, merkleRoot tlb.Bits256) (*boc.Cell, error) {
+	return buildStateInit(code, mintlessWalletData{
+		Owner:        owner.ToMsgAddress(),
+		JettonMaster: jettonMaster.ToMsgAddress(),
+		MerkleRoot:   merkleRoot,
+	})
+}
+
+// deriveJettonWallet derives a jetton wallet of the owner natively.
+// It returns false if the jetton master must be served by the emulator.
+func (h *Handler) deriveJettonWallet(ctx context.Context, jettonMaster, owner ton.AccountID) (derivedWallet, bool) {
+	template, ok := h.walletTemplate(ctx, jettonMaster)
+	if !ok {
+		return derivedWallet{}, false
+	}
+	wallet, err := template.derive(owner, jettonMaster)
+	if err != nil {
+		h.logger.Error("failed to derive jetton wallet", zap.String("owner", owner.ToRaw()), zap.Error(err))
+		return derivedWallet{}, false
+	}
+	if h.walletDerivations.Add(1)%walletSpotCheckRate == 0 && !h.spotCheckWallet(ctx, jettonMaster, owner, template, wallet) {
+		return derivedWallet{}, false
+	}
+	return wallet, true
+}
+
+// spotCheckWallet compares a derived wallet with the emulator.
+// On a mismatch the template is dropped, so the jetton master is served by the emulator until its state changes.
+func (h *Handler) spotCheckWallet(ctx context.Context, jettonMaster, owner ton.AccountID, template *walletTemplate, wallet derivedWallet) bool {
+	expected, err := h.emulateWallet(ctx, jettonMaster, owner)
+	if err != nil {
+		// the derived wallet is still served, the emulator might be overloaded.
+		walletSpotChecksMetric.WithLabelValues(jettonMaster.ToRaw(), "error").Inc()
+		h.logger.Warn("failed to check jetton wallet", zap.String("owner", owner.ToRaw()), zap.Error(err))
+		return true
+	}
+	if sameWallet(wallet, expected) {
+		walletSpotChecksMetric.WithLabelValues(jettonMaster.ToRaw(), "match").Inc()
+		return true
+	}
+	walletSpotChecksMetric.WithLabelValues(jettonMaster.ToRaw(), "mismatch").Inc()
+	h.logger.Error("derived jetton wallet doesn't match the emulator, falling back to emulator",
+		zap.String("jetton_master", jettonMaster.ToRaw()),
+		zap.String("owner", owner.ToRaw()),
+		zap.String("layout", template.layout.name()))
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	// a template resolved for a new state of the jetton master is kept.
+	if h.walletTemplates[jettonMaster] == template {
+		h.walletTemplates[jettonMaster] = nil
+	}
+	return false
+}
+
+// walletTemplateRetryInterval is how long a jetton master is served by the emulator
+// after its wallet layout failed to resolve.
+const walletTemplateRetryInterval = time.Minute
+
+func (h *Handler) walletTemplate(ctx context.Context, jettonMaster ton.AccountID) (*walletTemplate, bool) {
+	h.mu.RLock()
+	template, ok := h.walletTemplates[jettonMaster]
+	retryAt := h.walletTemplateRetries[jettonMaster]
+	h.mu.RUnlock()
+	if ok {
+		return template, template != nil
+	}
+	if time.Now().Before(retryAt) {
+		return nil, false
+	}
+	template, err := h.walletTemplateFlights.do(ctx, jettonMaster, func(ctx context.Context) (*walletTemplate, error) {
+		return h.resolveAndStoreWalletTemplate(ctx, jettonMaster)
+	})
+	if err != nil {
+		return nil, false
+	}
+	return template, template != nil
+}
+
+// resolveAndStoreWalletTemplate resolves a template once for all concurrent requests of the jetton master.
+// A failure is remembered, so the emulator isn't probed again until walletTemplateRetryInterval passes.
+func (h *Handler) resolveAndStoreWalletTemplate(ctx context.Context, jettonMaster ton.AccountID) (*walletTemplate, error) {
+	template, err := h.resolveWalletTemplate(ctx, jettonMaster)
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if err != nil {
+		// all callers have given up, it says nothing about the jetton master.
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		if h.walletTemplateRetries == nil {
+			h.walletTemplateRetries = map[ton.AccountID]time.Time{}
+		}
+		h.walletTemplateRetries[jettonMaster] = time.Now().Add(walletTemplateRetryInterval)
+		h.logger.Warn("failed to resolve jetton wallet layout", zap.String("jetton_master", jettonMaster.ToRaw()), zap.Error(err))
+		return nil, err
+	}
+	if h.walletTemplates == nil {
+		h.walletTemplates = map[ton.AccountID]*walletTemplate{}
+	}
+	h.walletTemplates[jettonMaster] = template
+	delete(h.walletTemplateRetries, jettonMaster)
+	if template == nil {
+		h.logger.Warn("unknown jetton wallet layout, falling back to emulator", zap.String("jetton_master", jettonMaster.ToRaw()))
+		return nil, nil
+	}
+	h.logger.Info("jetton wallets are derived natively",
+		zap.String("jetton_master", jettonMaster.ToRaw()),
+		zap.String("layout", template.layout.name()))
+	return template, nil
+}
+
+// resolveWalletTemplate finds a known layout that produces the same jetton wallets as the jetton master does.
+// It returns nil if there is no such layout.
+func (h *Handler) resolveWalletTemplate(ctx context.Context, jettonMaster ton.AccountID) (*walletTemplate, error) {
+	code, err := h.getJettonWalletCode(ctx, jettonMaster)
+	if err != nil {
+		return nil, err
+	}
+	merkleRoot, err := h.getMerkleRoot(ctx, jettonMaster)
+	if err != nil {
+		return nil, err
+	}
+	// random owners, so that a layout can't match a fixed set of probes by chance.
+	probes := make([]ton.AccountID, walletTemplateProbes)
+	expected := make([]derivedWallet, 0, len(probes))
+	for i := range probes {
+		if _, err := rand.Read(probes[i].Address[:]); err != nil {
+			return nil, err
+		}
+		wallet, err := h.emulateWallet(ctx, jettonMaster, probes[i])
+		if err != nil {
+			return nil, err
+		}
+		expected = append(expected, wallet)
+	}
+	for _, layout := range knownWalletLayouts {
+		template := &walletTemplate{layout: layout, code: code, merkleRoot: merkleRoot}
+		if matchesEmulator(template, jettonMaster, probes, expected) {
+			return template, nil
+		}
+	}
+	return nil, nil
+}
+
+// emulateWallet runs get-methods of the jetton master for the owner.
+func (h *Handler) emulateWallet(ctx context.Context, jettonMaster, owner ton.AccountID) (derivedWallet, error) {
+	stateInit, err := h.emulateStateInit(ctx, jettonMaster, owner)
+	if err != nil {
+		return derivedWallet{}, err
+	}
+	address, err := h.emulateJettonWallet(ctx, jettonMaster, owner)
+	if err != nil {
+		return derivedWallet{}, err
+	}
+	return derivedWallet{stateInit: stateInit, address: address}, nil
+}
+
+func matchesEmulator(template *walletTemplate, jettonMaster ton.AccountID, owners []ton.AccountID, expected []derivedWallet) bool {
+	for i, owner := range owners {
+		wallet, err := template.derive(owner, jettonMaster)
+		if err != nil || !sameWallet(wallet, expected[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+func sameWallet(derived, emulated derivedWallet) bool {
+	if derived.address != emulated.address {
+		return false
+	}
+	derivedHash, err := derived.stateInit.Hash()
+	if err != nil {
+		return false
+	}
+	emulatedHash, err := emulated.stateInit.Hash()
+	return err == nil && bytes.Equal(derivedHash, emulatedHash)
+}
+
+func (h *Handler) getJettonWalletCode(ctx context.Context, jettonMaster ton.AccountID) (*boc.Cell, error) {
+	executor, release, err := h.executor(ctx, jettonMaster)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+	_, value, err := abi.GetJettonData(ctx, executor, jettonMaster)
+	if err != nil {
+		return nil, err
+	}
+	result, ok := value.(abi.GetJettonDataResult)
+	if !ok {
+		return nil, fmt.Errorf("failed to get jetton data")
+	}
+	code := boc.Cell(result.JettonWalletCode)
+	return &code, nil
+}
@@ -0,0 +1,90 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"github.com/tonkeeper/tongo/tlb"
+	"github.com/tonkeeper/tongo/ton"
+)
+
+// TestHandler_deriveJettonWallet checks the native derivation against the emulator.
+func TestHandler_deriveJettonWallet(t *testing.T) {
+	h, jettonMaster := newSnapshotHandler(t)
+	owners := []ton.AccountID{
+		ton.MustParseAccountID("0:6ccd325a858c379693fae2bcaab1c2906831a4e10a6c3bb44ee8b615bca1d220"),
+		ton.MustParseAccountID("0:004bbd06fb606418d6e83916ee891845451335c3883b3aa6f502e24c5f5b1985"),
+		ton.MustParseAccountID("0:fed8483b9d943ddd3c816170317663c1bc0e4de644e9b141b8dcc0453fc9d232"),
+	}
+	for _, owner := range owners {
+		wallet, ok := h.deriveJettonWallet(context.Background(), jettonMaster, owner)
+		require.True(t, ok)
+
+		stateInit, err := h.emulateStateInit(context.Background(), jettonMaster, owner)
+		require.Nil(t, err)
+		expected, err := stateInit.Hash()
+		require.Nil(t, err)
+		derived, err := wallet.stateInit.Hash()
+		require.Nil(t, err)
+		require.Equal(t, expected, derived)
+
+		address, err := h.emulateJettonWallet(context.Background(), jettonMaster, owner)
+		require.Nil(t, err)
+		require.Equal(t, address, wallet.address)
+	}
+}
+
+func TestHandler_walletTemplateRetry(t *testing.T) {
+	h, jettonMaster := newSnapshotHandler(t)
+	state, ok := h.jettonMasterState(jettonMaster)
+	require.True(t, ok)
+	empty := emptyCellBoc(t)
+	h.setJettonMasterState(jettonMaster, [2]string{empty, empty})
+
+	_, ok = h.walletTemplate(context.Background(), jettonMaster)
+	require.False(t, ok)
+	retryAt := h.walletTemplateRetries[jettonMaster]
+	require.True(t, retryAt.After(time.Now()))
+
+	// the failure is cached, so the emulator isn't probed again.
+	_, ok = h.walletTemplate(context.Background(), jettonMaster)
+	require.False(t, ok)
+	require.Equal(t, retryAt, h.walletTemplateRetries[jettonMaster])
+
+	// a new state of the jetton master is resolved right away.
+	h.setJettonMasterState(jettonMaster, state)
+	require.NotContains(t, h.walletTemplateRetries, jettonMaster)
+	template, ok := h.walletTemplate(context.Background(), jettonMaster)
+	require.True(t, ok)
+	require.Equal(t, mintlessLayout{}, template.layout)
+}
+
+func TestHandler_spotCheckWallet(t *testing.T) {
+	rate := walletSpotCheckRate
+	walletSpotCheckRate = 1
+	defer func() { walletSpotCheckRate = rate }()
+
+	h, jettonMaster := newSnapshotHandler(t)
+	owner := ton.MustParseAccountID("0:6ccd325a858c379693fae2bcaab1c2906831a4e10a6c3bb44ee8b615bca1d220")
+	checks := func(result string) float64 {
+		return testutil.ToFloat64(walletSpotChecksMetric.WithLabelValues(jettonMaster.ToRaw(), result))
+	}
+	matches, mismatches := checks("match"), checks("mismatch")
+
+	_, ok := h.deriveJettonWallet(context.Background(), jettonMaster, owner)
+	require.True(t, ok)
+	require.Equal(t, matches+1, checks("match"))
+
+	// a template deriving wrong wallets is dropped by the first check.
+	template, ok := h.walletTemplate(context.Background(), jettonMaster)
+	require.True(t, ok)
+	h.walletTemplates[jettonMaster] = &walletTemplate{layout: template.layout, code: template.code, merkleRoot: tlb.Bits256{1}}
+	_, ok = h.deriveJettonWallet(context.Background(), jettonMaster, owner)
+	require.False(t, ok)
+	require.Equal(t, mismatches+1, checks("mismatch"))
+	require.Nil(t, h.walletTemplates[jettonMaster])
+	require.Contains(t, h.walletTemplates, jettonMaster)
+}
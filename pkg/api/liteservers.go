@@ -0,0 +1,324 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/tonkeeper/tongo/boc"
+	"github.com/tonkeeper/tongo/config"
+	"github.com/tonkeeper/tongo/liteapi"
+	"github.com/tonkeeper/tongo/liteclient"
+	"github.com/tonkeeper/tongo/tlb"
+	"github.com/tonkeeper/tongo/ton"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+var (
+	liteserverRequestsMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "claim_api_liteserver_requests_total",
+		Help: "Number of requests to a liteserver by result: ok, error or canceled",
+	}, []string{"server", "method", "result"})
+	liteserverRequestTimeMetric = promauto.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "claim_api_liteserver_request_time",
+		Help:    "Time of requests to a liteserver",
+		Buckets: []float64{0.01, 0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10},
+	}, []string{"server", "method"})
+	liteserverBreakerStateMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "claim_api_liteserver_breaker_state",
+		Help: "State of the circuit breaker of a liteserver: 0 - closed, 1 - half-open, 2 - open",
+	}, []string{"server"})
+	liteserverUpMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "claim_api_liteserver_up",
+		Help: "Result of the last health check of a liteserver",
+	}, []string{"server"})
+)
+
+var errNoLiteservers = errors.New("all liteservers are unavailable")
+
+const (
+	NetworkMainnet = "mainnet"
+	NetworkTestnet = "testnet"
+)
+
+// LiteserverConfig configures a pool of liteservers.
+// Servers are taken from the first non-empty source:
+// Servers, ConfigFile, the LITE_SERVERS environment variable and the public config of Network.
+type LiteserverConfig struct {
+	Servers []config.LiteServer
+	// ConfigFile is a global config file of a network.
+	ConfigFile string
+	// Network is either NetworkMainnet or NetworkTestnet, empty means NetworkMainnet.
+	Network string
+	// HealthCheckInterval defines how often every liteserver is checked. Zero disables health checks.
+	HealthCheckInterval time.Duration
+	// FailureThreshold is a number of consecutive failures that opens the circuit breaker of a liteserver.
+	FailureThreshold int
+	// OpenTimeout is how long a liteserver with an open circuit breaker gets no requests.
+	OpenTimeout time.Duration
+}
+
+func (c LiteserverConfig) liteservers() ([]config.LiteServer, error) {
+	if len(c.Servers) > 0 {
+		return c.Servers, nil
+	}
+	if c.ConfigFile != "" {
+		file, err := config.ParseConfigFile(c.ConfigFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %v: %w", c.ConfigFile, err)
+		}
+		return file.LiteServers, nil
+	}
+	if value, ok := os.LookupEnv(liteapi.LiteServerEnvName); ok {
+		return config.ParseLiteServersEnvVar(value)
+	}
+	var options liteapi.Options
+	switch c.Network {
+	case "", NetworkMainnet:
+		if err := liteapi.Mainnet()(&options); err != nil {
+			return nil, err
+		}
+	case NetworkTestnet:
+		if err := liteapi.Testnet()(&options); err != nil {
+			return nil, err
+		}
+	default:
+		return nil, fmt.Errorf("unknown network %q", c.Network)
+	}
+	return options.LiteServers, nil
+}
+
+// liteserverClient is a connection to a single liteserver.
+type liteserverClient interface {
+	Backend
+	GetMasterchainInfo(ctx context.Context) (liteclient.LiteServerMasterchainInfoC, error)
+	GetBlockHeader(ctx context.Context, blockID ton.BlockIDExt, mode uint32) (tlb.BlockInfo, error)
+}
+
+// LiteserverPool is a Backend sending every request to one of the healthy liteservers
+// and failing over to the next one if the request fails.
+// Every liteserver has a circuit breaker, so a bad node stops getting requests after a few failures.
+type LiteserverPool struct {
+	logger              *zap.Logger
+	servers             []*liteserver
+	healthCheckInterval time.Duration
+	next                atomic.Uint32
+}
+
+var _ Backend = (*LiteserverPool)(nil)
+
+type liteserver struct {
+	host    string
+	breaker *circuitBreaker
+	connect func(ctx context.Context) (liteserverClient, error)
+
+	mu     sync.Mutex
+	client liteserverClient
+	// connects coalesces concurrent dials, so a slow dial doesn't hold mu.
+	connects flightGroup[struct{}, liteserverClient]
+}
+
+// NewLiteserverPool creates a pool of the configured liteservers.
+// Connections are established lazily, so an unavailable liteserver doesn't prevent the start.
+func NewLiteserverPool(logger *zap.Logger, conf LiteserverConfig) (*LiteserverPool, error) {
+	servers, err := conf.liteservers()
+	if err != nil {
+		return nil, err
+	}
+	if len(servers) == 0 {
+		return nil, fmt.Errorf("no liteservers configured")
+	}
+	p := &LiteserverPool{logger: logger, healthCheckInterval: conf.HealthCheckInterval}
+	for _, server := range servers {
+		server := server
+		p.servers = append(p.servers, &liteserver{
+			host:     server.Host,
+			breaker:  newCircuitBreaker(conf.FailureThreshold, conf.OpenTimeout),
+			connects: flightGroup[struct{}, liteserverClient]{name: "liteserverConnect"},
+			connect: func(ctx context.Context) (liteserverClient, error) {
+				return liteapi.NewClient(
+					liteapi.WithLiteServers([]config.LiteServer{server}),
+					liteapi.WithInitializationContext(ctx),
+					liteapi.WithMaxConnectionsNumber(1))
+			},
+		})
+	}
+	return p, nil
+}
+
+// Run checks liteservers periodically until the context is canceled.
+func (p *LiteserverPool) Run(ctx context.Context) {
+	if p.healthCheckInterval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(p.healthCheckInterval)
+	defer ticker.Stop()
+	for {
+		p.checkHealth(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *LiteserverPool) checkHealth(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, p.healthCheckInterval)
+	defer cancel()
+	var wg sync.WaitGroup
+	for _, s := range p.servers {
+		wg.Add(1)
+		go func(s *liteserver) {
+			defer wg.Done()
+			// a health check bypasses the breaker, so a recovered liteserver gets requests again without waiting.
+			err := p.call(ctx, s, "GetMasterchainInfo", func(client liteserverClient) error {
+				_, err := client.GetMasterchainInfo(ctx)
+				return err
+			})
+			if err != nil {
+				liteserverUpMetric.WithLabelValues(s.host).Set(0)
+				p.logger.Warn("liteserver health check failed", zap.String("server", s.host), zap.Error(err))
+				return
+			}
+			liteserverUpMetric.WithLabelValues(s.host).Set(1)
+		}(s)
+	}
+	wg.Wait()
+}
+
+// do sends a request to liteservers one by one, starting from the next one in round-robin order,
+// until one of them succeeds.
+func (p *LiteserverPool) do(ctx context.Context, method string, f func(client liteserverClient) error) error {
+	err := errNoLiteservers
+	start := int(p.next.Add(1))
+	for i := range p.servers {
+		s := p.servers[(start+i)%len(p.servers)]
+		if !s.breaker.allow() {
+			continue
+		}
+		if err = p.call(ctx, s, method, f); err == nil {
+			return nil
+		}
+		if ctx.Err() != nil {
+			return err
+		}
+	}
+	return err
+}
+
+func (p *LiteserverPool) call(ctx context.Context, s *liteserver, method string, f func(client liteserverClient) error) (err error) {
+	ctx, span := tracer.Start(ctx, "liteserver."+method, trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(attribute.String("server", s.host)))
+	defer func() { endSpan(span, err) }()
+
+	start := time.Now()
+	client, err := s.get(ctx)
+	if err == nil {
+		err = f(client)
+	}
+	liteserverRequestTimeMetric.WithLabelValues(s.host, method).Observe(time.Since(start).Seconds())
+	switch {
+	case err == nil:
+		s.breaker.success()
+		liteserverRequestsMetric.WithLabelValues(s.host, method, "ok").Inc()
+	case ctx.Err() != nil:
+		s.breaker.abort()
+		liteserverRequestsMetric.WithLabelValues(s.host, method, "canceled").Inc()
+	default:
+		if s.breaker.failure() {
+			// a connection reconnects by itself, so the client is kept.
+			p.logger.Warn("liteserver is disabled after failures", zap.String("server", s.host), zap.Error(err))
+		}
+		liteserverRequestsMetric.WithLabelValues(s.host, method, "error").Inc()
+	}
+	liteserverBreakerStateMetric.WithLabelValues(s.host).Set(float64(s.breaker.currentState()))
+	return err
+}
+
+func (s *liteserver) get(ctx context.Context) (liteserverClient, error) {
+	if client := s.currentClient(); client != nil {
+		return client, nil
+	}
+	return s.connects.do(ctx, struct{}{}, func(ctx context.Context) (liteserverClient, error) {
+		// a previous dial might have finished after the check above.
+		if client := s.currentClient(); client != nil {
+			return client, nil
+		}
+		client, err := s.connect(ctx)
+		if err != nil {
+			return nil, err
+		}
+		s.mu.Lock()
+		s.client = client
+		s.mu.Unlock()
+		return client, nil
+	})
+}
+
+func (s *liteserver) currentClient() liteserverClient {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.client
+}
+
+// Ping checks that at least one liteserver responds.
+func (p *LiteserverPool) Ping(ctx context.Context) error {
+	return p.do(ctx, "GetMasterchainInfo", func(client liteserverClient) error {
+		_, err := client.GetMasterchainInfo(ctx)
+		return err
+	})
+}
+
+// LastKeyBlockSeqno returns the seqno of the last key block of the masterchain.
+func (p *LiteserverPool) LastKeyBlockSeqno(ctx context.Context) (uint32, error) {
+	var seqno uint32
+	err := p.do(ctx, "GetBlockHeader", func(client liteserverClient) error {
+		info, err := client.GetMasterchainInfo(ctx)
+		if err != nil {
+			return err
+		}
+		header, err := client.GetBlockHeader(ctx, info.Last.ToBlockIdExt(), 0)
+		if err != nil {
+			return err
+		}
+		seqno = lastKeyBlockSeqno(header.SeqNo, header.KeyBlock, header.PrevKeyBlockSeqno)
+		return nil
+	})
+	return seqno, err
+}
+
+func (p *LiteserverPool) GetAccountState(ctx context.Context, accountID ton.AccountID) (tlb.ShardAccount, error) {
+	var account tlb.ShardAccount
+	err := p.do(ctx, "GetAccountState", func(client liteserverClient) (err error) {
+		account, err = client.GetAccountState(ctx, accountID)
+		return err
+	})
+	return account, err
+}
+
+func (p *LiteserverPool) GetConfigAll(ctx context.Context, mode liteapi.ConfigMode) (tlb.ConfigParams, error) {
+	var params tlb.ConfigParams
+	err := p.do(ctx, "GetConfigAll", func(client liteserverClient) (err error) {
+		params, err = client.GetConfigAll(ctx, mode)
+		return err
+	})
+	return params, err
+}
+
+func (p *LiteserverPool) GetLibraries(ctx context.Context, libraryList []ton.Bits256) (map[ton.Bits256]*boc.Cell, error) {
+	var libraries map[ton.Bits256]*boc.Cell
+	err := p.do(ctx, "GetLibraries", func(client liteserverClient) (err error) {
+		libraries, err = client.GetLibraries(ctx, libraryList)
+		return err
+	})
+	return libraries, err
+}
@@ -0,0 +1,151 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tonkeeper/tongo/liteclient"
+	"github.com/tonkeeper/tongo/tlb"
+	"github.com/tonkeeper/tongo/ton"
+	"go.uber.org/zap"
+)
+
+type mockLiteserver struct {
+	*MemoryBackend
+
+	mu    sync.Mutex
+	down  bool
+	calls int
+}
+
+func (m *mockLiteserver) setDown(down bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.down = down
+}
+
+func (m *mockLiteserver) call() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls++
+	if m.down {
+		return fmt.Errorf("liteserver is down")
+	}
+	return nil
+}
+
+func (m *mockLiteserver) GetAccountState(ctx context.Context, accountID ton.AccountID) (tlb.ShardAccount, error) {
+	if err := m.call(); err != nil {
+		return tlb.ShardAccount{}, err
+	}
+	return m.MemoryBackend.GetAccountState(ctx, accountID)
+}
+
+func (m *mockLiteserver) GetMasterchainInfo(ctx context.Context) (liteclient.LiteServerMasterchainInfoC, error) {
+	return liteclient.LiteServerMasterchainInfoC{}, m.call()
+}
+
+// GetBlockHeader reports the key block of the MemoryBackend as the previous key block of any block.
+func (m *mockLiteserver) GetBlockHeader(ctx context.Context, blockID ton.BlockIDExt, mode uint32) (tlb.BlockInfo, error) {
+	if err := m.call(); err != nil {
+		return tlb.BlockInfo{}, err
+	}
+	var header tlb.BlockInfo
+	header.PrevKeyBlockSeqno, _ = m.MemoryBackend.LastKeyBlockSeqno(ctx)
+	return header, nil
+}
+
+func newMockLiteserverPool(servers ...*mockLiteserver) *LiteserverPool {
+	p := &LiteserverPool{logger: zap.NewNop()}
+	for i, server := range servers {
+		server := server
+		p.servers = append(p.servers, &liteserver{
+			host:    fmt.Sprintf("127.0.0.%v:1", i),
+			breaker: newCircuitBreaker(2, time.Hour),
+			connect: func(ctx context.Context) (liteserverClient, error) {
+				return server, nil
+			},
+		})
+	}
+	return p
+}
+
+func TestLiteserverPool(t *testing.T) {
+	accountID := ton.MustParseAccountID("0:6ccd325a858c379693fae2bcaab1c2906831a4e10a6c3bb44ee8b615bca1d220")
+	good := &mockLiteserver{MemoryBackend: NewMemoryBackend()}
+	bad := &mockLiteserver{MemoryBackend: NewMemoryBackend(), down: true}
+	p := newMockLiteserverPool(bad, good)
+	ctx := context.Background()
+
+	// every request fails over to the good liteserver.
+	for i := 0; i < 10; i++ {
+		_, err := p.GetAccountState(ctx, accountID)
+		require.Nil(t, err)
+	}
+	require.Equal(t, 10, good.calls)
+	// the bad one is disabled after two failures.
+	require.Equal(t, 2, bad.calls)
+	require.Equal(t, breakerOpen, p.servers[0].breaker.currentState())
+
+	// a health check enables a recovered liteserver.
+	bad.setDown(false)
+	p.healthCheckInterval = time.Second
+	p.checkHealth(ctx)
+	require.Equal(t, breakerClosed, p.servers[0].breaker.currentState())
+
+	good.setDown(true)
+	bad.setDown(true)
+	for i := 0; i < 2; i++ {
+		_, err := p.GetAccountState(ctx, accountID)
+		require.NotNil(t, err)
+	}
+	_, err := p.GetAccountState(ctx, accountID)
+	require.ErrorIs(t, err, errNoLiteservers)
+}
+
+func TestLiteserver_get(t *testing.T) {
+	server := &mockLiteserver{MemoryBackend: NewMemoryBackend()}
+	release := make(chan struct{})
+	var dials atomic.Int32
+	s := &liteserver{
+		host:    "127.0.0.1:1",
+		breaker: newCircuitBreaker(2, time.Hour),
+		connect: func(ctx context.Context) (liteserverClient, error) {
+			dials.Add(1)
+			<-release
+			return server, nil
+		},
+	}
+
+	// a caller gives up on a slow dial without waiting for it.
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	_, err := s.get(ctx)
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+
+	// concurrent callers share a single dial.
+	var wg sync.WaitGroup
+	errs := make(chan error, 10)
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := s.get(context.Background())
+			errs <- err
+		}()
+	}
+	require.Eventually(t, func() bool { return dials.Load() == 2 }, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		require.Nil(t, err)
+	}
+	require.Equal(t, int32(2), dials.Load())
+	require.Equal(t, liteserverClient(server), s.currentClient())
+}
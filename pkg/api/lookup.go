@@ -0,0 +1,123 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+
+	"github.com/tonkeeper/tongo/ton"
+
+	"github.com/tonkeeper/claim-api-go/pkg/api/oas"
+	"github.com/tonkeeper/claim-api-go/pkg/prover"
+)
+
+// lookupConcurrency limits a number of wallets of a single lookup converted in parallel.
+const lookupConcurrency = 16
+
+func (h *Handler) LookupWallets(ctx context.Context, req *oas.LookupWalletsReq) (*oas.WalletLookupResult, error) {
+	return h.lookupWallets(ctx, h.defaultCampaign, req.Addresses, req.ClaimStatus.Value)
+}
+
+func (h *Handler) LookupCampaignWallets(ctx context.Context, req *oas.LookupCampaignWalletsReq, params oas.LookupCampaignWalletsParams) (*oas.WalletLookupResult, error) {
+	c, err := h.campaign(params.JettonMaster)
+	if err != nil {
+		return nil, err
+	}
+	return h.lookupWallets(ctx, c, req.Addresses, req.ClaimStatus.Value)
+}
+
+// lookupWallets works like walletInfo for every address,
+// but proofs missing in the caches are requested from the prover with a single batch.
+// Claim statuses are opt-in because each of them costs a request to the blockchain.
+func (h *Handler) lookupWallets(ctx context.Context, c *campaign, addresses []string, withClaimStatus bool) (*oas.WalletLookupResult, error) {
+	proofs := make([]prover.WalletAirdrop, len(addresses))
+	errs := make([]error, len(addresses))
+	// pending maps an account to positions of its addresses, the same account might be requested several times.
+	pending := make(map[ton.AccountID][]int)
+	var accountIDs []ton.AccountID
+	for i, address := range addresses {
+		accountID, err := ton.ParseAccountID(address)
+		if err != nil {
+			errs[i] = BadRequest("failed to parse account id")
+			continue
+		}
+		proof, ok, err := c.cachedProof(accountID)
+		if ok || err != nil {
+			proofs[i], errs[i] = proof, err
+			continue
+		}
+		if _, ok := pending[accountID]; !ok {
+			accountIDs = append(accountIDs, accountID)
+		}
+		pending[accountID] = append(pending[accountID], i)
+	}
+
+	if len(accountIDs) > 0 {
+		responseCh := make(chan prover.BatchProofResponse, 1)
+		err := h.enqueue(ctx, c, c.prover.Queue(), prover.BatchProofRequest{
+			Ctx:        ctx,
+			AccountIDs: accountIDs,
+			ResponseCh: responseCh,
+		})
+		if err != nil && ctx.Err() != nil {
+			return nil, requestTimeout("queue")
+		}
+		if err != nil {
+			return nil, err
+		}
+		select {
+		case <-ctx.Done():
+			return nil, requestTimeout("prover")
+		case resp := <-responseCh:
+			for j, accountID := range accountIDs {
+				proof, err := c.handleProofResponse(accountID, resp.Responses[j])
+				for _, i := range pending[accountID] {
+					proofs[i], errs[i] = proof, err
+				}
+			}
+		}
+	}
+
+	items := make([]oas.WalletLookupResultWalletsItem, len(addresses))
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, lookupConcurrency)
+	for i, address := range addresses {
+		items[i].Address = address
+		if errs[i] != nil {
+			continue
+		}
+		wg.Add(1)
+		semaphore <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-semaphore }()
+			info, err := h.convertToWalletInfo(ctx, c, proofs[i], withClaimStatus)
+			if err != nil {
+				errs[i] = InternalError(err)
+				return
+			}
+			items[i].Wallet = oas.NewOptWalletInfo(*info)
+		}(i)
+	}
+	wg.Wait()
+
+	for i := range items {
+		items[i].Status, items[i].Error = lookupStatus(errs[i])
+	}
+	return &oas.WalletLookupResult{Wallets: items}, nil
+}
+
+func lookupStatus(err error) (oas.WalletLookupResultWalletsItemStatus, oas.OptString) {
+	if err == nil {
+		return oas.WalletLookupResultWalletsItemStatusFound, oas.OptString{}
+	}
+	var statusErr *oas.ErrorStatusCode
+	if !errors.As(err, &statusErr) {
+		return oas.WalletLookupResultWalletsItemStatusError, oas.NewOptString(err.Error())
+	}
+	if statusErr.StatusCode == http.StatusNotFound {
+		return oas.WalletLookupResultWalletsItemStatusNotFound, oas.NewOptString(statusErr.Response.Error)
+	}
+	return oas.WalletLookupResultWalletsItemStatusError, oas.NewOptString(statusErr.Response.Error)
+}
@@ -0,0 +1,69 @@
+package api
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tonkeeper/tongo/abi"
+	"github.com/tonkeeper/tongo/ton"
+	"go.uber.org/zap"
+
+	"github.com/tonkeeper/claim-api-go/pkg/api/oas"
+)
+
+func TestHandler_lookupWallets(t *testing.T) {
+	c, err := newCampaign(zap.NewNop(), CampaignConfig{AirdropFilename: "../prover/testdata/airdropData.boc"}, 2)
+	require.Nil(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.prover.Run(ctx)
+	h := &Handler{
+		logger:          zap.NewNop(),
+		campaigns:       map[ton.AccountID]*campaign{c.jettonMaster: c},
+		defaultCampaign: c,
+	}
+
+	missing := ton.MustParseAccountID("0:ff41b315c634b4ea4814b9262499567d36e9c7b13da09476f11a41d94e2cb700")
+	result, err := h.lookupWallets(ctx, c, []string{"invalid", missing.ToRaw(), missing.ToRaw()}, false)
+	require.Nil(t, err)
+	require.Equal(t, 3, len(result.Wallets))
+	require.Equal(t, oas.WalletLookupResultWalletsItemStatusError, result.Wallets[0].Status)
+	require.Equal(t, "invalid", result.Wallets[0].Address)
+	require.Equal(t, oas.WalletLookupResultWalletsItemStatusNotFound, result.Wallets[1].Status)
+	require.Equal(t, oas.WalletLookupResultWalletsItemStatusNotFound, result.Wallets[2].Status)
+	require.False(t, result.Wallets[1].Wallet.Set)
+
+	_, ok := c.keyNotFoundCache.Get(missing)
+	require.True(t, ok)
+	result, err = h.lookupWallets(ctx, c, []string{missing.ToRaw()}, false)
+	require.Nil(t, err)
+	require.Equal(t, oas.WalletLookupResultWalletsItemStatusNotFound, result.Wallets[0].Status)
+}
+
+func TestHandler_lookupWalletsClaimStatus(t *testing.T) {
+	h, jettonMaster := newSnapshotHandler(t)
+	c, err := newCampaign(zap.NewNop(), CampaignConfig{AirdropFilename: "../prover/testdata/airdropData.boc", JettonMaster: jettonMaster}, 1)
+	require.Nil(t, err)
+	h.campaigns[jettonMaster] = c
+	var calls atomic.Int32
+	h.jettonWalletExecutor = func(ctx context.Context, jettonWallet ton.AccountID) (abi.Executor, error) {
+		calls.Add(1)
+		return nil, errNotDeployed
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.prover.Run(ctx)
+
+	owner := ton.MustParseAccountID("0:050b89727f74efd71e3f5c396c76c6df7ee71aced7c2ec7a8c55bb8bba8d1399")
+	result, err := h.lookupWallets(ctx, c, []string{owner.ToRaw()}, false)
+	require.Nil(t, err)
+	require.Equal(t, oas.WalletLookupResultWalletsItemStatusFound, result.Wallets[0].Status)
+	require.False(t, result.Wallets[0].Wallet.Value.ClaimStatus.Set)
+	require.Equal(t, int32(0), calls.Load())
+
+	result, err = h.lookupWallets(ctx, c, []string{owner.ToRaw()}, true)
+	require.Nil(t, err)
+	require.True(t, result.Wallets[0].Wallet.Value.ClaimStatus.Set)
+}
@@ -0,0 +1,191 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/tonkeeper/tongo/abi"
+	"github.com/tonkeeper/tongo/tlb"
+	"github.com/tonkeeper/tongo/ton"
+	"go.uber.org/zap"
+)
+
+var merkleRootMatchMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+	Name: "claim_api_merkle_root_match",
+	Help: "1 if the merkle root of the airdrop dictionary matches the one stored in the jetton master, 0 otherwise",
+}, []string{"jetton_master"})
+
+// merkleRootRetryInterval is how often merkle roots are verified until every campaign has a result.
+var merkleRootRetryInterval = 10 * time.Second
+
+// merkleRootStatus is a result of the last successful comparison of merkle roots of a campaign.
+type merkleRootStatus int32
+
+const (
+	// merkleRootUnknown means that the merkle root hasn't been verified yet.
+	merkleRootUnknown merkleRootStatus = iota
+	merkleRootMatch
+	merkleRootMismatch
+)
+
+func (s merkleRootStatus) String() string {
+	switch s {
+	case merkleRootMatch:
+		return "match"
+	case merkleRootMismatch:
+		return "mismatch"
+	default:
+		return "unknown"
+	}
+}
+
+func (c *campaign) merkleRootStatus() merkleRootStatus {
+	return merkleRootStatus(c.merkleRoot.Load())
+}
+
+func (c *campaign) setMerkleRootStatus(status merkleRootStatus) {
+	c.merkleRoot.Store(int32(status))
+	match := 0.0
+	if status == merkleRootMatch {
+		match = 1
+	}
+	merkleRootMatchMetric.WithLabelValues(c.jettonMaster.ToRaw()).Set(match)
+}
+
+// verifyMerkleRoots checks that every campaign serves proofs for the merkle root stored in its jetton master.
+func (h *Handler) verifyMerkleRoots(ctx context.Context) error {
+	var errs []error
+	for _, c := range h.campaigns {
+		if err := h.verifyMerkleRoot(ctx, c); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// verifyMerkleRoot stores the result of the comparison in the campaign.
+// A failure to get the merkle root of the jetton master keeps the previous result.
+func (h *Handler) verifyMerkleRoot(ctx context.Context, c *campaign) error {
+	err := h.matchMerkleRoot(ctx, c.jettonMaster, c.prover.MerkleRoot())
+	switch {
+	case err == nil:
+		c.setMerkleRootStatus(merkleRootMatch)
+	case errors.Is(err, errMerkleRootMismatch):
+		c.setMerkleRootStatus(merkleRootMismatch)
+	}
+	return err
+}
+
+// merkleRootsVerified reports whether every campaign has a result of the comparison.
+func (h *Handler) merkleRootsVerified() bool {
+	for _, c := range h.campaigns {
+		if c.merkleRootStatus() == merkleRootUnknown {
+			return false
+		}
+	}
+	return true
+}
+
+var errMerkleRootMismatch = errors.New("merkle root mismatch")
+
+// matchMerkleRoot checks that the jetton master stores the given merkle root.
+func (h *Handler) matchMerkleRoot(ctx context.Context, jettonMaster ton.AccountID, root tlb.Bits256) error {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	onchainRoot, err := h.getMerkleRoot(ctx, jettonMaster)
+	if err != nil {
+		return fmt.Errorf("failed to get merkle root of %v: %w", jettonMaster.ToRaw(), err)
+	}
+	if onchainRoot != root {
+		return fmt.Errorf("%w for %v: jetton master has %v, airdrop dictionary has %v",
+			errMerkleRootMismatch, jettonMaster.ToRaw(), onchainRoot.Hex(), root.Hex())
+	}
+	return nil
+}
+
+// runMerkleRootVerification periodically compares merkle roots of the campaigns with their jetton masters.
+// Until every campaign has a result, the comparison is retried every merkleRootRetryInterval.
+// Zero interval disables periodic checks once all results are known.
+func (h *Handler) runMerkleRootVerification(ctx context.Context, interval time.Duration) {
+	for {
+		wait := interval
+		if !h.merkleRootsVerified() {
+			wait = merkleRootRetryInterval
+		}
+		if wait <= 0 {
+			return
+		}
+		timer := time.NewTimer(wait)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return
+		case <-timer.C:
+			if err := h.verifyMerkleRoots(ctx); err != nil {
+				h.logger.Error("merkle root verification failed", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (h *Handler) getMerkleRoot(ctx context.Context, jettonMaster ton.AccountID) (tlb.Bits256, error) {
+	executor, release, err := h.executor(ctx, jettonMaster)
+	if err != nil {
+		return tlb.Bits256{}, err
+	}
+	defer release()
+	_, value, err := GetMintlessAirdropHashmapRoot(ctx, executor, jettonMaster)
+	if err != nil {
+		return tlb.Bits256{}, err
+	}
+	result, ok := value.(GetMintlessAirdropHashmapRootResult)
+	if !ok {
+		return tlb.Bits256{}, fmt.Errorf("failed to get merkle root")
+	}
+	root := big.Int(result.Root)
+	if root.Sign() < 0 || root.BitLen() > 256 {
+		return tlb.Bits256{}, fmt.Errorf("invalid merkle root: %v", root.String())
+	}
+	var bits tlb.Bits256
+	root.FillBytes(bits[:])
+	return bits, nil
+}
+
+type GetMintlessAirdropHashmapRootResult struct {
+	Root tlb.Int257
+}
+
+func GetMintlessAirdropHashmapRoot(ctx context.Context, executor abi.Executor, reqAccountID ton.AccountID) (string, any, error) {
+	stack := tlb.VmStack{}
+
+	// MethodID = 79463 for "get_mintless_airdrop_hashmap_root" method
+	errCode, stack, err := executor.RunSmcMethodByID(ctx, reqAccountID, 79463, stack)
+	if err != nil {
+		return "", nil, err
+	}
+	if errCode != 0 && errCode != 1 {
+		return "", nil, fmt.Errorf("method execution failed with code: %v", errCode)
+	}
+	for _, f := range []func(tlb.VmStack) (string, any, error){DecodeGetMintlessAirdropHashmapRootResult} {
+		s, r, err := f(stack)
+		if err == nil {
+			return s, r, nil
+		}
+	}
+	return "", nil, fmt.Errorf("can not decode outputs")
+}
+
+func DecodeGetMintlessAirdropHashmapRootResult(stack tlb.VmStack) (resultType string, resultAny any, err error) {
+	if len(stack) != 1 || (stack[0].SumType != "VmStkTinyInt" && stack[0].SumType != "VmStkInt") {
+		return "", nil, fmt.Errorf("invalid stack format")
+	}
+	var result GetMintlessAirdropHashmapRootResult
+	err = stack.Unmarshal(&result)
+	return "GetMintlessAirdropHashmapRootResult", result, err
+}
@@ -0,0 +1,113 @@
+package api
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tonkeeper/tongo/boc"
+	"github.com/tonkeeper/tongo/tlb"
+	"github.com/tonkeeper/tongo/ton"
+	"go.uber.org/zap"
+)
+
+// snapshotMasterCells returns code and data of the jetton master pinned in testdata/snapshot.json.
+func snapshotMasterCells(t *testing.T, jettonMaster ton.AccountID) (*boc.Cell, *boc.Cell) {
+	snapshot, err := ReadSnapshot("testdata/snapshot.json")
+	require.Nil(t, err)
+	state, ok := snapshot.jettonMasterState(jettonMaster)
+	require.True(t, ok)
+	code, err := boc.DeserializeSinglRootBase64(state[0])
+	require.Nil(t, err)
+	data, err := boc.DeserializeSinglRootBase64(state[1])
+	require.Nil(t, err)
+	return code, data
+}
+
+func merkleRootData(t *testing.T, merkleRoot tlb.Bits256) *boc.Cell {
+	data := boc.NewCell()
+	require.Nil(t, data.WriteBytes(merkleRoot[:]))
+	return data
+}
+
+func TestHandler_verifyMerkleRoots(t *testing.T) {
+	h, jettonMaster := newSnapshotHandler(t)
+	c, err := newCampaign(zap.NewNop(), CampaignConfig{AirdropFilename: "../prover/testdata/airdropData.boc", JettonMaster: jettonMaster}, 1)
+	require.Nil(t, err)
+	h.campaigns[jettonMaster] = c
+	require.Equal(t, merkleRootUnknown, c.merkleRootStatus())
+
+	require.Nil(t, h.verifyMerkleRoots(context.Background()))
+	require.Equal(t, merkleRootMatch, c.merkleRootStatus())
+
+	code, data := snapshotMasterCells(t, jettonMaster)
+	codeBoc, err := code.ToBocBase64()
+	require.Nil(t, err)
+	otherData, err := merkleRootData(t, tlb.Bits256{1}).ToBocBase64()
+	require.Nil(t, err)
+	h.setJettonMasterState(jettonMaster, [2]string{codeBoc, otherData})
+	require.ErrorIs(t, h.verifyMerkleRoots(context.Background()), errMerkleRootMismatch)
+	require.Equal(t, merkleRootMismatch, c.merkleRootStatus())
+
+	// a jetton master whose state can't be fetched keeps the previous result.
+	h.mu.Lock()
+	delete(h.jettonMasterStateCache, jettonMaster)
+	delete(h.emulatorPools, jettonMaster)
+	h.mu.Unlock()
+	err = h.verifyMerkleRoots(context.Background())
+	require.NotNil(t, err)
+	require.NotErrorIs(t, err, errMerkleRootMismatch)
+	require.Equal(t, merkleRootMismatch, c.merkleRootStatus())
+
+	dataBoc, err := data.ToBocBase64()
+	require.Nil(t, err)
+	h.setJettonMasterState(jettonMaster, [2]string{codeBoc, dataBoc})
+	require.Nil(t, h.verifyMerkleRoots(context.Background()))
+	require.Equal(t, merkleRootMatch, c.merkleRootStatus())
+}
+
+func TestHandler_runMerkleRootVerification(t *testing.T) {
+	interval := merkleRootRetryInterval
+	merkleRootRetryInterval = 10 * time.Millisecond
+	defer func() { merkleRootRetryInterval = interval }()
+
+	_, jettonMaster := newSnapshotHandler(t)
+	code, data := snapshotMasterCells(t, jettonMaster)
+	server := &mockLiteserver{MemoryBackend: NewMemoryBackend(), down: true}
+	server.SetAccount(jettonMaster, code, data, 1)
+	c, err := newCampaign(zap.NewNop(), CampaignConfig{AirdropFilename: "../prover/testdata/airdropData.boc", JettonMaster: jettonMaster}, 1)
+	require.Nil(t, err)
+	h := &Handler{
+		logger:                 zap.NewNop(),
+		campaigns:              map[ton.AccountID]*campaign{jettonMaster: c},
+		backend:                newMockLiteserverPool(server),
+		libraries:              server,
+		jettonMasterStateCache: map[ton.AccountID][2]string{},
+		walletTemplates:        map[ton.AccountID]*walletTemplate{},
+	}
+	require.Nil(t, h.setConfig(testBlockchainConfig(t)))
+
+	// a liteserver failure at start leaves the result unknown instead of failing.
+	require.NotNil(t, h.verifyMerkleRoots(context.Background()))
+	require.Equal(t, merkleRootUnknown, c.merkleRootStatus())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		// periodic checks are disabled, only the retries run.
+		h.runMerkleRootVerification(ctx, 0)
+	}()
+	server.setDown(false)
+	require.Eventually(t, func() bool {
+		return c.merkleRootStatus() == merkleRootMatch
+	}, time.Second, 10*time.Millisecond)
+	// the result is known, so there is nothing left to do.
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("verification keeps running")
+	}
+}
@@ -1,6 +1,11 @@
 package api
 
 import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
 	"github.com/ogen-go/ogen/middleware"
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
@@ -41,3 +46,33 @@ func ogenMetricsMiddleware(req middleware.Request, next middleware.Next) (middle
 	defer t.ObserveDuration()
 	return next(req)
 }
+
+func ogenTimeoutMiddleware(timeout time.Duration) middleware.Middleware {
+	return func(req middleware.Request, next middleware.Next) (middleware.Response, error) {
+		ctx, cancel := context.WithTimeout(req.Context, timeout)
+		defer cancel()
+		req.Context = ctx
+		return next(req)
+	}
+}
+
+// retryAfterSeconds is a hint for clients how long to back off when the service is overloaded.
+const retryAfterSeconds = 1
+
+// retryAfterMiddleware adds Retry-After to every 503 response.
+func retryAfterMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		next.ServeHTTP(&retryAfterWriter{ResponseWriter: w}, r)
+	})
+}
+
+type retryAfterWriter struct {
+	http.ResponseWriter
+}
+
+func (w *retryAfterWriter) WriteHeader(statusCode int) {
+	if statusCode == http.StatusServiceUnavailable {
+		w.Header().Set("Retry-After", strconv.Itoa(retryAfterSeconds))
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
@@ -0,0 +1,19 @@
+// Code generated by ogen, DO NOT EDIT.
+
+package oas
+
+// setDefaults set default value of fields.
+func (s *LookupCampaignWalletsReq) setDefaults() {
+	{
+		val := bool(false)
+		s.ClaimStatus.SetTo(val)
+	}
+}
+
+// setDefaults set default value of fields.
+func (s *LookupWalletsReq) setDefaults() {
+	{
+		val := bool(false)
+		s.ClaimStatus.SetTo(val)
+	}
+}
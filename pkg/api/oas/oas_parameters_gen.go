@@ -15,6 +15,360 @@ import (
 	"github.com/ogen-go/ogen/validate"
 )
 
+// GetCampaignStatsParams is parameters of getCampaignStats operation.
+type GetCampaignStatsParams struct {
+	JettonMaster string
+}
+
+func unpackGetCampaignStatsParams(packed middleware.Parameters) (params GetCampaignStatsParams) {
+	{
+		key := middleware.ParameterKey{
+			Name: "jetton_master",
+			In:   "path",
+		}
+		params.JettonMaster = packed[key].(string)
+	}
+	return params
+}
+
+func decodeGetCampaignStatsParams(args [1]string, argsEscaped bool, r *http.Request) (params GetCampaignStatsParams, _ error) {
+	// Decode path: jetton_master.
+	if err := func() error {
+		param := args[0]
+		if argsEscaped {
+			unescaped, err := url.PathUnescape(args[0])
+			if err != nil {
+				return errors.Wrap(err, "unescape path")
+			}
+			param = unescaped
+		}
+		if len(param) > 0 {
+			d := uri.NewPathDecoder(uri.PathDecoderConfig{
+				Param:   "jetton_master",
+				Value:   param,
+				Style:   uri.PathStyleSimple,
+				Explode: false,
+			})
+
+			if err := func() error {
+				val, err := d.DecodeValue()
+				if err != nil {
+					return err
+				}
+
+				c, err := conv.ToString(val)
+				if err != nil {
+					return err
+				}
+
+				params.JettonMaster = c
+				return nil
+			}(); err != nil {
+				return err
+			}
+		} else {
+			return validate.ErrFieldRequired
+		}
+		return nil
+	}(); err != nil {
+		return params, &ogenerrors.DecodeParamError{
+			Name: "jetton_master",
+			In:   "path",
+			Err:  err,
+		}
+	}
+	return params, nil
+}
+
+// GetCampaignWalletInfoParams is parameters of getCampaignWalletInfo operation.
+type GetCampaignWalletInfoParams struct {
+	JettonMaster string
+	Address      string
+}
+
+func unpackGetCampaignWalletInfoParams(packed middleware.Parameters) (params GetCampaignWalletInfoParams) {
+	{
+		key := middleware.ParameterKey{
+			Name: "jetton_master",
+			In:   "path",
+		}
+		params.JettonMaster = packed[key].(string)
+	}
+	{
+		key := middleware.ParameterKey{
+			Name: "address",
+			In:   "path",
+		}
+		params.Address = packed[key].(string)
+	}
+	return params
+}
+
+func decodeGetCampaignWalletInfoParams(args [2]string, argsEscaped bool, r *http.Request) (params GetCampaignWalletInfoParams, _ error) {
+	// Decode path: jetton_master.
+	if err := func() error {
+		param := args[0]
+		if argsEscaped {
+			unescaped, err := url.PathUnescape(args[0])
+			if err != nil {
+				return errors.Wrap(err, "unescape path")
+			}
+			param = unescaped
+		}
+		if len(param) > 0 {
+			d := uri.NewPathDecoder(uri.PathDecoderConfig{
+				Param:   "jetton_master",
+				Value:   param,
+				Style:   uri.PathStyleSimple,
+				Explode: false,
+			})
+
+			if err := func() error {
+				val, err := d.DecodeValue()
+				if err != nil {
+					return err
+				}
+
+				c, err := conv.ToString(val)
+				if err != nil {
+					return err
+				}
+
+				params.JettonMaster = c
+				return nil
+			}(); err != nil {
+				return err
+			}
+		} else {
+			return validate.ErrFieldRequired
+		}
+		return nil
+	}(); err != nil {
+		return params, &ogenerrors.DecodeParamError{
+			Name: "jetton_master",
+			In:   "path",
+			Err:  err,
+		}
+	}
+	// Decode path: address.
+	if err := func() error {
+		param := args[1]
+		if argsEscaped {
+			unescaped, err := url.PathUnescape(args[1])
+			if err != nil {
+				return errors.Wrap(err, "unescape path")
+			}
+			param = unescaped
+		}
+		if len(param) > 0 {
+			d := uri.NewPathDecoder(uri.PathDecoderConfig{
+				Param:   "address",
+				Value:   param,
+				Style:   uri.PathStyleSimple,
+				Explode: false,
+			})
+
+			if err := func() error {
+				val, err := d.DecodeValue()
+				if err != nil {
+					return err
+				}
+
+				c, err := conv.ToString(val)
+				if err != nil {
+					return err
+				}
+
+				params.Address = c
+				return nil
+			}(); err != nil {
+				return err
+			}
+		} else {
+			return validate.ErrFieldRequired
+		}
+		return nil
+	}(); err != nil {
+		return params, &ogenerrors.DecodeParamError{
+			Name: "address",
+			In:   "path",
+			Err:  err,
+		}
+	}
+	return params, nil
+}
+
+// GetCampaignWalletsParams is parameters of getCampaignWallets operation.
+type GetCampaignWalletsParams struct {
+	JettonMaster string
+	NextFrom     string
+	Count        int
+}
+
+func unpackGetCampaignWalletsParams(packed middleware.Parameters) (params GetCampaignWalletsParams) {
+	{
+		key := middleware.ParameterKey{
+			Name: "jetton_master",
+			In:   "path",
+		}
+		params.JettonMaster = packed[key].(string)
+	}
+	{
+		key := middleware.ParameterKey{
+			Name: "next_from",
+			In:   "query",
+		}
+		params.NextFrom = packed[key].(string)
+	}
+	{
+		key := middleware.ParameterKey{
+			Name: "count",
+			In:   "query",
+		}
+		params.Count = packed[key].(int)
+	}
+	return params
+}
+
+func decodeGetCampaignWalletsParams(args [1]string, argsEscaped bool, r *http.Request) (params GetCampaignWalletsParams, _ error) {
+	q := uri.NewQueryDecoder(r.URL.Query())
+	// Decode path: jetton_master.
+	if err := func() error {
+		param := args[0]
+		if argsEscaped {
+			unescaped, err := url.PathUnescape(args[0])
+			if err != nil {
+				return errors.Wrap(err, "unescape path")
+			}
+			param = unescaped
+		}
+		if len(param) > 0 {
+			d := uri.NewPathDecoder(uri.PathDecoderConfig{
+				Param:   "jetton_master",
+				Value:   param,
+				Style:   uri.PathStyleSimple,
+				Explode: false,
+			})
+
+			if err := func() error {
+				val, err := d.DecodeValue()
+				if err != nil {
+					return err
+				}
+
+				c, err := conv.ToString(val)
+				if err != nil {
+					return err
+				}
+
+				params.JettonMaster = c
+				return nil
+			}(); err != nil {
+				return err
+			}
+		} else {
+			return validate.ErrFieldRequired
+		}
+		return nil
+	}(); err != nil {
+		return params, &ogenerrors.DecodeParamError{
+			Name: "jetton_master",
+			In:   "path",
+			Err:  err,
+		}
+	}
+	// Decode query: next_from.
+	if err := func() error {
+		cfg := uri.QueryParameterDecodingConfig{
+			Name:    "next_from",
+			Style:   uri.QueryStyleForm,
+			Explode: true,
+		}
+
+		if err := q.HasParam(cfg); err == nil {
+			if err := q.DecodeParam(cfg, func(d uri.Decoder) error {
+				val, err := d.DecodeValue()
+				if err != nil {
+					return err
+				}
+
+				c, err := conv.ToString(val)
+				if err != nil {
+					return err
+				}
+
+				params.NextFrom = c
+				return nil
+			}); err != nil {
+				return err
+			}
+		} else {
+			return validate.ErrFieldRequired
+		}
+		return nil
+	}(); err != nil {
+		return params, &ogenerrors.DecodeParamError{
+			Name: "next_from",
+			In:   "query",
+			Err:  err,
+		}
+	}
+	// Decode query: count.
+	if err := func() error {
+		cfg := uri.QueryParameterDecodingConfig{
+			Name:    "count",
+			Style:   uri.QueryStyleForm,
+			Explode: true,
+		}
+
+		if err := q.HasParam(cfg); err == nil {
+			if err := q.DecodeParam(cfg, func(d uri.Decoder) error {
+				val, err := d.DecodeValue()
+				if err != nil {
+					return err
+				}
+
+				c, err := conv.ToInt(val)
+				if err != nil {
+					return err
+				}
+
+				params.Count = c
+				return nil
+			}); err != nil {
+				return err
+			}
+			if err := func() error {
+				if err := (validate.Int{
+					MinSet:        true,
+					Min:           5,
+					MaxSet:        true,
+					Max:           10000,
+					MinExclusive:  false,
+					MaxExclusive:  false,
+					MultipleOfSet: false,
+					MultipleOf:    0,
+				}).Validate(int64(params.Count)); err != nil {
+					return errors.Wrap(err, "int")
+				}
+				return nil
+			}(); err != nil {
+				return err
+			}
+		} else {
+			return validate.ErrFieldRequired
+		}
+		return nil
+	}(); err != nil {
+		return params, &ogenerrors.DecodeParamError{
+			Name: "count",
+			In:   "query",
+			Err:  err,
+		}
+	}
+	return params, nil
+}
+
 // GetWalletInfoParams is parameters of getWalletInfo operation.
 type GetWalletInfoParams struct {
 	Address string
@@ -197,3 +551,68 @@ func decodeGetWalletsParams(args [0]string, argsEscaped bool, r *http.Request) (
 	}
 	return params, nil
 }
+
+// LookupCampaignWalletsParams is parameters of lookupCampaignWallets operation.
+type LookupCampaignWalletsParams struct {
+	JettonMaster string
+}
+
+func unpackLookupCampaignWalletsParams(packed middleware.Parameters) (params LookupCampaignWalletsParams) {
+	{
+		key := middleware.ParameterKey{
+			Name: "jetton_master",
+			In:   "path",
+		}
+		params.JettonMaster = packed[key].(string)
+	}
+	return params
+}
+
+func decodeLookupCampaignWalletsParams(args [1]string, argsEscaped bool, r *http.Request) (params LookupCampaignWalletsParams, _ error) {
+	// Decode path: jetton_master.
+	if err := func() error {
+		param := args[0]
+		if argsEscaped {
+			unescaped, err := url.PathUnescape(args[0])
+			if err != nil {
+				return errors.Wrap(err, "unescape path")
+			}
+			param = unescaped
+		}
+		if len(param) > 0 {
+			d := uri.NewPathDecoder(uri.PathDecoderConfig{
+				Param:   "jetton_master",
+				Value:   param,
+				Style:   uri.PathStyleSimple,
+				Explode: false,
+			})
+
+			if err := func() error {
+				val, err := d.DecodeValue()
+				if err != nil {
+					return err
+				}
+
+				c, err := conv.ToString(val)
+				if err != nil {
+					return err
+				}
+
+				params.JettonMaster = c
+				return nil
+			}(); err != nil {
+				return err
+			}
+		} else {
+			return validate.ErrFieldRequired
+		}
+		return nil
+	}(); err != nil {
+		return params, &ogenerrors.DecodeParamError{
+			Name: "jetton_master",
+			In:   "path",
+			Err:  err,
+		}
+	}
+	return params, nil
+}
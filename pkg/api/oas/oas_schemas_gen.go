@@ -5,12 +5,118 @@ package oas
 import (
 	"fmt"
 	"io"
+
+	"github.com/go-faster/errors"
 )
 
 func (s *ErrorStatusCode) Error() string {
 	return fmt.Sprintf("code %d: %+v", s.StatusCode, s.Response)
 }
 
+// Ref: #/components/schemas/CampaignStats
+type CampaignStats struct {
+	JettonMaster string `json:"jetton_master"`
+	MerkleRoot   string `json:"merkle_root"`
+	Recipients   int64  `json:"recipients"`
+	TotalAmount  string `json:"total_amount"`
+	MinAmount    string `json:"min_amount"`
+	MaxAmount    string `json:"max_amount"`
+	// The lower median for an even number of recipients.
+	MedianAmount string        `json:"median_amount"`
+	StartFrom    TimeHistogram `json:"start_from"`
+	ExpiredAt    TimeHistogram `json:"expired_at"`
+}
+
+// GetJettonMaster returns the value of JettonMaster.
+func (s *CampaignStats) GetJettonMaster() string {
+	return s.JettonMaster
+}
+
+// GetMerkleRoot returns the value of MerkleRoot.
+func (s *CampaignStats) GetMerkleRoot() string {
+	return s.MerkleRoot
+}
+
+// GetRecipients returns the value of Recipients.
+func (s *CampaignStats) GetRecipients() int64 {
+	return s.Recipients
+}
+
+// GetTotalAmount returns the value of TotalAmount.
+func (s *CampaignStats) GetTotalAmount() string {
+	return s.TotalAmount
+}
+
+// GetMinAmount returns the value of MinAmount.
+func (s *CampaignStats) GetMinAmount() string {
+	return s.MinAmount
+}
+
+// GetMaxAmount returns the value of MaxAmount.
+func (s *CampaignStats) GetMaxAmount() string {
+	return s.MaxAmount
+}
+
+// GetMedianAmount returns the value of MedianAmount.
+func (s *CampaignStats) GetMedianAmount() string {
+	return s.MedianAmount
+}
+
+// GetStartFrom returns the value of StartFrom.
+func (s *CampaignStats) GetStartFrom() TimeHistogram {
+	return s.StartFrom
+}
+
+// GetExpiredAt returns the value of ExpiredAt.
+func (s *CampaignStats) GetExpiredAt() TimeHistogram {
+	return s.ExpiredAt
+}
+
+// SetJettonMaster sets the value of JettonMaster.
+func (s *CampaignStats) SetJettonMaster(val string) {
+	s.JettonMaster = val
+}
+
+// SetMerkleRoot sets the value of MerkleRoot.
+func (s *CampaignStats) SetMerkleRoot(val string) {
+	s.MerkleRoot = val
+}
+
+// SetRecipients sets the value of Recipients.
+func (s *CampaignStats) SetRecipients(val int64) {
+	s.Recipients = val
+}
+
+// SetTotalAmount sets the value of TotalAmount.
+func (s *CampaignStats) SetTotalAmount(val string) {
+	s.TotalAmount = val
+}
+
+// SetMinAmount sets the value of MinAmount.
+func (s *CampaignStats) SetMinAmount(val string) {
+	s.MinAmount = val
+}
+
+// SetMaxAmount sets the value of MaxAmount.
+func (s *CampaignStats) SetMaxAmount(val string) {
+	s.MaxAmount = val
+}
+
+// SetMedianAmount sets the value of MedianAmount.
+func (s *CampaignStats) SetMedianAmount(val string) {
+	s.MedianAmount = val
+}
+
+// SetStartFrom sets the value of StartFrom.
+func (s *CampaignStats) SetStartFrom(val TimeHistogram) {
+	s.StartFrom = val
+}
+
+// SetExpiredAt sets the value of ExpiredAt.
+func (s *CampaignStats) SetExpiredAt(val TimeHistogram) {
+	s.ExpiredAt = val
+}
+
 type Error struct {
 	Error string `json:"error"`
 }
@@ -65,6 +171,104 @@ func (s GetApiInfoOK) Read(p []byte) (n int, err error) {
 	return s.Data.Read(p)
 }
 
+type LookupCampaignWalletsReq struct {
+	Addresses []string `json:"addresses"`
+	// Include on-chain claim statuses, every found wallet costs a request to the blockchain.
+	ClaimStatus OptBool `json:"claim_status"`
+}
+
+// GetAddresses returns the value of Addresses.
+func (s *LookupCampaignWalletsReq) GetAddresses() []string {
+	return s.Addresses
+}
+
+// GetClaimStatus returns the value of ClaimStatus.
+func (s *LookupCampaignWalletsReq) GetClaimStatus() OptBool {
+	return s.ClaimStatus
+}
+
+// SetAddresses sets the value of Addresses.
+func (s *LookupCampaignWalletsReq) SetAddresses(val []string) {
+	s.Addresses = val
+}
+
+// SetClaimStatus sets the value of ClaimStatus.
+func (s *LookupCampaignWalletsReq) SetClaimStatus(val OptBool) {
+	s.ClaimStatus = val
+}
+
+type LookupWalletsReq struct {
+	Addresses []string `json:"addresses"`
+	// Include on-chain claim statuses, every found wallet costs a request to the blockchain.
+	ClaimStatus OptBool `json:"claim_status"`
+}
+
+// GetAddresses returns the value of Addresses.
+func (s *LookupWalletsReq) GetAddresses() []string {
+	return s.Addresses
+}
+
+// GetClaimStatus returns the value of ClaimStatus.
+func (s *LookupWalletsReq) GetClaimStatus() OptBool {
+	return s.ClaimStatus
+}
+
+// SetAddresses sets the value of Addresses.
+func (s *LookupWalletsReq) SetAddresses(val []string) {
+	s.Addresses = val
+}
+
+// SetClaimStatus sets the value of ClaimStatus.
+func (s *LookupWalletsReq) SetClaimStatus(val OptBool) {
+	s.ClaimStatus = val
+}
+
+// NewOptBool returns new OptBool with value set to v.
+func NewOptBool(v bool) OptBool {
+	return OptBool{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptBool is optional bool.
+type OptBool struct {
+	Value bool
+	Set   bool
+}
+
+// IsSet returns true if OptBool was set.
+func (o OptBool) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptBool) Reset() {
+	var v bool
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptBool) SetTo(v bool) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptBool) Get() (v bool, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptBool) Or(d bool) bool {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
 // NewOptString returns new OptString with value set to v.
 func NewOptString(v string) OptString {
 	return OptString{
@@ -111,6 +315,98 @@ func (o OptString) Or(d string) string {
 	return d
 }
 
+// NewOptWalletInfo returns new OptWalletInfo with value set to v.
+func NewOptWalletInfo(v WalletInfo) OptWalletInfo {
+	return OptWalletInfo{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptWalletInfo is optional WalletInfo.
+type OptWalletInfo struct {
+	Value WalletInfo
+	Set   bool
+}
+
+// IsSet returns true if OptWalletInfo was set.
+func (o OptWalletInfo) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptWalletInfo) Reset() {
+	var v WalletInfo
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptWalletInfo) SetTo(v WalletInfo) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptWalletInfo) Get() (v WalletInfo, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptWalletInfo) Or(d WalletInfo) WalletInfo {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
+// NewOptWalletInfoClaimStatus returns new OptWalletInfoClaimStatus with value set to v.
+func NewOptWalletInfoClaimStatus(v WalletInfoClaimStatus) OptWalletInfoClaimStatus {
+	return OptWalletInfoClaimStatus{
+		Value: v,
+		Set:   true,
+	}
+}
+
+// OptWalletInfoClaimStatus is optional WalletInfoClaimStatus.
+type OptWalletInfoClaimStatus struct {
+	Value WalletInfoClaimStatus
+	Set   bool
+}
+
+// IsSet returns true if OptWalletInfoClaimStatus was set.
+func (o OptWalletInfoClaimStatus) IsSet() bool { return o.Set }
+
+// Reset unsets value.
+func (o *OptWalletInfoClaimStatus) Reset() {
+	var v WalletInfoClaimStatus
+	o.Value = v
+	o.Set = false
+}
+
+// SetTo sets value to v.
+func (o *OptWalletInfoClaimStatus) SetTo(v WalletInfoClaimStatus) {
+	o.Set = true
+	o.Value = v
+}
+
+// Get returns value and boolean that denotes whether value was set.
+func (o OptWalletInfoClaimStatus) Get() (v WalletInfoClaimStatus, ok bool) {
+	if !o.Set {
+		return v, false
+	}
+	return o.Value, true
+}
+
+// Or returns value if set, or given parameter if does not.
+func (o OptWalletInfoClaimStatus) Or(d WalletInfoClaimStatus) WalletInfoClaimStatus {
+	if v, ok := o.Get(); ok {
+		return v
+	}
+	return d
+}
+
 // NewOptWalletInfoCompressedInfo returns new OptWalletInfoCompressedInfo with value set to v.
 func NewOptWalletInfoCompressedInfo(v WalletInfoCompressedInfo) OptWalletInfoCompressedInfo {
 	return OptWalletInfoCompressedInfo{
@@ -157,12 +453,42 @@ func (o OptWalletInfoCompressedInfo) Or(d WalletInfoCompressedInfo) WalletInfoCo
 	return d
 }
 
+type TimeHistogram []TimeHistogramItem
+
+type TimeHistogramItem struct {
+	// Unix time of the beginning of a day.
+	From  int64 `json:"from"`
+	Count int64 `json:"count"`
+}
+
+// GetFrom returns the value of From.
+func (s *TimeHistogramItem) GetFrom() int64 {
+	return s.From
+}
+
+// GetCount returns the value of Count.
+func (s *TimeHistogramItem) GetCount() int64 {
+	return s.Count
+}
+
+// SetFrom sets the value of From.
+func (s *TimeHistogramItem) SetFrom(val int64) {
+	s.From = val
+}
+
+// SetCount sets the value of Count.
+func (s *TimeHistogramItem) SetCount(val int64) {
+	s.Count = val
+}
+
 // Ref: #/components/schemas/WalletInfo
 type WalletInfo struct {
-	Owner          string                      `json:"owner"`
-	JettonWallet   string                      `json:"jetton_wallet"`
-	CustomPayload  string                      `json:"custom_payload"`
-	StateInit      OptString                   `json:"state_init"`
+	Owner         string    `json:"owner"`
+	JettonWallet  string    `json:"jetton_wallet"`
+	CustomPayload string    `json:"custom_payload"`
+	StateInit     OptString `json:"state_init"`
+	// On-chain status of the claim, it is omitted if the status is unknown.
+	ClaimStatus    OptWalletInfoClaimStatus    `json:"claim_status"`
 	CompressedInfo OptWalletInfoCompressedInfo `json:"compressed_info"`
 }
 
@@ -186,6 +512,11 @@ func (s *WalletInfo) GetStateInit() OptString {
 	return s.StateInit
 }
 
+// GetClaimStatus returns the value of ClaimStatus.
+func (s *WalletInfo) GetClaimStatus() OptWalletInfoClaimStatus {
+	return s.ClaimStatus
+}
+
 // GetCompressedInfo returns the value of CompressedInfo.
 func (s *WalletInfo) GetCompressedInfo() OptWalletInfoCompressedInfo {
 	return s.CompressedInfo
@@ -211,11 +542,72 @@ func (s *WalletInfo) SetStateInit(val OptString) {
 	s.StateInit = val
 }
 
+// SetClaimStatus sets the value of ClaimStatus.
+func (s *WalletInfo) SetClaimStatus(val OptWalletInfoClaimStatus) {
+	s.ClaimStatus = val
+}
+
 // SetCompressedInfo sets the value of CompressedInfo.
 func (s *WalletInfo) SetCompressedInfo(val OptWalletInfoCompressedInfo) {
 	s.CompressedInfo = val
 }
 
+// On-chain status of the claim, it is omitted if the status is unknown.
+type WalletInfoClaimStatus string
+
+const (
+	WalletInfoClaimStatusUnclaimed  WalletInfoClaimStatus = "unclaimed"
+	WalletInfoClaimStatusClaimed    WalletInfoClaimStatus = "claimed"
+	WalletInfoClaimStatusNotStarted WalletInfoClaimStatus = "not_started"
+	WalletInfoClaimStatusExpired    WalletInfoClaimStatus = "expired"
+)
+
+// AllValues returns all WalletInfoClaimStatus values.
+func (WalletInfoClaimStatus) AllValues() []WalletInfoClaimStatus {
+	return []WalletInfoClaimStatus{
+		WalletInfoClaimStatusUnclaimed,
+		WalletInfoClaimStatusClaimed,
+		WalletInfoClaimStatusNotStarted,
+		WalletInfoClaimStatusExpired,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s WalletInfoClaimStatus) MarshalText() ([]byte, error) {
+	switch s {
+	case WalletInfoClaimStatusUnclaimed:
+		return []byte(s), nil
+	case WalletInfoClaimStatusClaimed:
+		return []byte(s), nil
+	case WalletInfoClaimStatusNotStarted:
+		return []byte(s), nil
+	case WalletInfoClaimStatusExpired:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *WalletInfoClaimStatus) UnmarshalText(data []byte) error {
+	switch WalletInfoClaimStatus(data) {
+	case WalletInfoClaimStatusUnclaimed:
+		*s = WalletInfoClaimStatusUnclaimed
+		return nil
+	case WalletInfoClaimStatusClaimed:
+		*s = WalletInfoClaimStatusClaimed
+		return nil
+	case WalletInfoClaimStatusNotStarted:
+		*s = WalletInfoClaimStatusNotStarted
+		return nil
+	case WalletInfoClaimStatusExpired:
+		*s = WalletInfoClaimStatusExpired
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
+
 type WalletInfoCompressedInfo struct {
 	Amount    string `json:"amount"`
 	StartFrom string `json:"start_from"`
@@ -338,3 +730,114 @@ func (s *WalletListWalletsItemCompressedInfo) SetStartFrom(val string) {
 func (s *WalletListWalletsItemCompressedInfo) SetExpiredAt(val string) {
 	s.ExpiredAt = val
 }
+
+// Ref: #/components/schemas/WalletLookupResult
+type WalletLookupResult struct {
+	// Results in the order of the requested addresses.
+	Wallets []WalletLookupResultWalletsItem `json:"wallets"`
+}
+
+// GetWallets returns the value of Wallets.
+func (s *WalletLookupResult) GetWallets() []WalletLookupResultWalletsItem {
+	return s.Wallets
+}
+
+// SetWallets sets the value of Wallets.
+func (s *WalletLookupResult) SetWallets(val []WalletLookupResultWalletsItem) {
+	s.Wallets = val
+}
+
+type WalletLookupResultWalletsItem struct {
+	Address string                              `json:"address"`
+	Status  WalletLookupResultWalletsItemStatus `json:"status"`
+	Wallet  OptWalletInfo                       `json:"wallet"`
+	Error   OptString                           `json:"error"`
+}
+
+// GetAddress returns the value of Address.
+func (s *WalletLookupResultWalletsItem) GetAddress() string {
+	return s.Address
+}
+
+// GetStatus returns the value of Status.
+func (s *WalletLookupResultWalletsItem) GetStatus() WalletLookupResultWalletsItemStatus {
+	return s.Status
+}
+
+// GetWallet returns the value of Wallet.
+func (s *WalletLookupResultWalletsItem) GetWallet() OptWalletInfo {
+	return s.Wallet
+}
+
+// GetError returns the value of Error.
+func (s *WalletLookupResultWalletsItem) GetError() OptString {
+	return s.Error
+}
+
+// SetAddress sets the value of Address.
+func (s *WalletLookupResultWalletsItem) SetAddress(val string) {
+	s.Address = val
+}
+
+// SetStatus sets the value of Status.
+func (s *WalletLookupResultWalletsItem) SetStatus(val WalletLookupResultWalletsItemStatus) {
+	s.Status = val
+}
+
+// SetWallet sets the value of Wallet.
+func (s *WalletLookupResultWalletsItem) SetWallet(val OptWalletInfo) {
+	s.Wallet = val
+}
+
+// SetError sets the value of Error.
+func (s *WalletLookupResultWalletsItem) SetError(val OptString) {
+	s.Error = val
+}
+
+type WalletLookupResultWalletsItemStatus string
+
+const (
+	WalletLookupResultWalletsItemStatusFound    WalletLookupResultWalletsItemStatus = "found"
+	WalletLookupResultWalletsItemStatusNotFound WalletLookupResultWalletsItemStatus = "not_found"
+	WalletLookupResultWalletsItemStatusError    WalletLookupResultWalletsItemStatus = "error"
+)
+
+// AllValues returns all WalletLookupResultWalletsItemStatus values.
+func (WalletLookupResultWalletsItemStatus) AllValues() []WalletLookupResultWalletsItemStatus {
+	return []WalletLookupResultWalletsItemStatus{
+		WalletLookupResultWalletsItemStatusFound,
+		WalletLookupResultWalletsItemStatusNotFound,
+		WalletLookupResultWalletsItemStatusError,
+	}
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (s WalletLookupResultWalletsItemStatus) MarshalText() ([]byte, error) {
+	switch s {
+	case WalletLookupResultWalletsItemStatusFound:
+		return []byte(s), nil
+	case WalletLookupResultWalletsItemStatusNotFound:
+		return []byte(s), nil
+	case WalletLookupResultWalletsItemStatusError:
+		return []byte(s), nil
+	default:
+		return nil, errors.Errorf("invalid value: %q", s)
+	}
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (s *WalletLookupResultWalletsItemStatus) UnmarshalText(data []byte) error {
+	switch WalletLookupResultWalletsItemStatus(data) {
+	case WalletLookupResultWalletsItemStatusFound:
+		*s = WalletLookupResultWalletsItemStatusFound
+		return nil
+	case WalletLookupResultWalletsItemStatusNotFound:
+		*s = WalletLookupResultWalletsItemStatusNotFound
+		return nil
+	case WalletLookupResultWalletsItemStatusError:
+		*s = WalletLookupResultWalletsItemStatusError
+		return nil
+	default:
+		return errors.Errorf("invalid value: %q", data)
+	}
+}
@@ -12,6 +12,22 @@ type Handler interface {
 	//
 	// GET /
 	GetApiInfo(ctx context.Context) (GetApiInfoOK, error)
+	// GetCampaignStats implements getCampaignStats operation.
+	//
+	// GET /{jetton_master}/stats
+	GetCampaignStats(ctx context.Context, params GetCampaignStatsParams) (*CampaignStats, error)
+	// GetCampaignWalletInfo implements getCampaignWalletInfo operation.
+	//
+	// GET /{jetton_master}/wallet/{address}
+	GetCampaignWalletInfo(ctx context.Context, params GetCampaignWalletInfoParams) (*WalletInfo, error)
+	// GetCampaignWallets implements getCampaignWallets operation.
+	//
+	// GET /{jetton_master}/wallets
+	GetCampaignWallets(ctx context.Context, params GetCampaignWalletsParams) (*WalletList, error)
+	// GetStats implements getStats operation.
+	//
+	// GET /stats
+	GetStats(ctx context.Context) (*CampaignStats, error)
 	// GetWalletInfo implements getWalletInfo operation.
 	//
 	// GET /wallet/{address}
@@ -20,6 +36,14 @@ type Handler interface {
 	//
 	// GET /wallets
 	GetWallets(ctx context.Context, params GetWalletsParams) (*WalletList, error)
+	// LookupCampaignWallets implements lookupCampaignWallets operation.
+	//
+	// POST /{jetton_master}/wallets/lookup
+	LookupCampaignWallets(ctx context.Context, req *LookupCampaignWalletsReq, params LookupCampaignWalletsParams) (*WalletLookupResult, error)
+	// LookupWallets implements lookupWallets operation.
+	//
+	// POST /wallets/lookup
+	LookupWallets(ctx context.Context, req *LookupWalletsReq) (*WalletLookupResult, error)
 	// NewError creates *ErrorStatusCode from error returned by handler.
 	//
 	// Used for common default response.
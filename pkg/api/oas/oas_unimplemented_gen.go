@@ -20,6 +20,34 @@ func (UnimplementedHandler) GetApiInfo(ctx context.Context) (r GetApiInfoOK, _ e
 	return r, ht.ErrNotImplemented
 }
 
+// GetCampaignStats implements getCampaignStats operation.
+//
+// GET /{jetton_master}/stats
+func (UnimplementedHandler) GetCampaignStats(ctx context.Context, params GetCampaignStatsParams) (r *CampaignStats, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// GetCampaignWalletInfo implements getCampaignWalletInfo operation.
+//
+// GET /{jetton_master}/wallet/{address}
+func (UnimplementedHandler) GetCampaignWalletInfo(ctx context.Context, params GetCampaignWalletInfoParams) (r *WalletInfo, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// GetCampaignWallets implements getCampaignWallets operation.
+//
+// GET /{jetton_master}/wallets
+func (UnimplementedHandler) GetCampaignWallets(ctx context.Context, params GetCampaignWalletsParams) (r *WalletList, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// GetStats implements getStats operation.
+//
+// GET /stats
+func (UnimplementedHandler) GetStats(ctx context.Context) (r *CampaignStats, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
 // GetWalletInfo implements getWalletInfo operation.
 //
 // GET /wallet/{address}
@@ -34,6 +62,20 @@ func (UnimplementedHandler) GetWallets(ctx context.Context, params GetWalletsPar
 	return r, ht.ErrNotImplemented
 }
 
+// LookupCampaignWallets implements lookupCampaignWallets operation.
+//
+// POST /{jetton_master}/wallets/lookup
+func (UnimplementedHandler) LookupCampaignWallets(ctx context.Context, req *LookupCampaignWalletsReq, params LookupCampaignWalletsParams) (r *WalletLookupResult, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
+// LookupWallets implements lookupWallets operation.
+//
+// POST /wallets/lookup
+func (UnimplementedHandler) LookupWallets(ctx context.Context, req *LookupWalletsReq) (r *WalletLookupResult, _ error) {
+	return r, ht.ErrNotImplemented
+}
+
 // NewError creates *ErrorStatusCode from error returned by handler.
 //
 // Used for common default response.
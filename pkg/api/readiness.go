@@ -0,0 +1,184 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/tonkeeper/tongo/tlb"
+	"github.com/tonkeeper/tongo/ton"
+	"go.uber.org/zap"
+)
+
+const (
+	readinessOK   = "ok"
+	readinessFail = "fail"
+	// readinessTimeout limits all checks of a single readiness probe.
+	readinessTimeout = 3 * time.Second
+	// readinessEmulatorTTL is how long a result of the emulator check is reused by the next probes.
+	readinessEmulatorTTL = 30 * time.Second
+)
+
+// readinessCanaryOwner is an owner whose jetton wallet is derived by the emulator check.
+var readinessCanaryOwner = ton.MustParseAccountID("0:0000000000000000000000000000000000000000000000000000000000000000")
+
+// pinger is implemented by backends that can check their connection to the blockchain.
+type pinger interface {
+	Ping(ctx context.Context) error
+}
+
+// cachedCheck keeps a result of an expensive readiness check.
+type cachedCheck struct {
+	mu        sync.Mutex
+	checkedAt time.Time
+	err       error
+}
+
+// run returns the cached result if it is younger than ttl and runs the check otherwise.
+// Concurrent probes wait for a single run.
+func (c *cachedCheck) run(ctx context.Context, ttl time.Duration, check func(ctx context.Context) error) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if !c.checkedAt.IsZero() && time.Since(c.checkedAt) < ttl {
+		return c.err
+	}
+	err := check(ctx)
+	// a probe that ran out of time says nothing about the emulator.
+	if ctx.Err() != nil {
+		return err
+	}
+	c.checkedAt, c.err = time.Now(), err
+	return err
+}
+
+type readinessCheck struct {
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+type readiness struct {
+	Status string                    `json:"status"`
+	Checks map[string]readinessCheck `json:"checks"`
+}
+
+// readiness runs all readiness checks concurrently.
+func (h *Handler) readiness(ctx context.Context) readiness {
+	ctx, cancel := context.WithTimeout(ctx, readinessTimeout)
+	defer cancel()
+
+	checks := map[string]func(ctx context.Context) error{
+		"dictionary":  h.checkDictionaries,
+		"merkle_root": h.checkMerkleRoots,
+		"emulator":    h.checkEmulator,
+		"queue":       h.checkQueues,
+	}
+	// a snapshot doesn't need the blockchain.
+	if h.backend != nil {
+		checks["backend"] = h.checkBackend
+	}
+	result := readiness{Status: readinessOK, Checks: map[string]readinessCheck{}}
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for name, check := range checks {
+		wg.Add(1)
+		go func(name string, check func(ctx context.Context) error) {
+			defer wg.Done()
+			status := readinessCheck{Status: readinessOK}
+			if err := check(ctx); err != nil {
+				status = readinessCheck{Status: readinessFail, Error: err.Error()}
+			}
+			mu.Lock()
+			defer mu.Unlock()
+			result.Checks[name] = status
+			if status.Status != readinessOK {
+				result.Status = readinessFail
+			}
+		}(name, check)
+	}
+	wg.Wait()
+	return result
+}
+
+// checkDictionaries checks that every prover is running with a loaded dictionary.
+func (h *Handler) checkDictionaries(ctx context.Context) error {
+	for _, c := range h.campaigns {
+		if !c.prover.Running() {
+			return fmt.Errorf("prover of %v is not running", c.jettonMaster.ToRaw())
+		}
+		if c.prover.MerkleRoot() == (tlb.Bits256{}) {
+			return fmt.Errorf("merkle root of %v is not computed", c.jettonMaster.ToRaw())
+		}
+	}
+	return nil
+}
+
+// checkMerkleRoots fails if the last verification of a campaign found that its dictionary
+// doesn't match the jetton master, every proof of such a campaign would fail on-chain.
+// A campaign that hasn't been verified yet fails the check too, its verification is retried in the background.
+func (h *Handler) checkMerkleRoots(ctx context.Context) error {
+	for _, c := range h.campaigns {
+		switch c.merkleRootStatus() {
+		case merkleRootMismatch:
+			return fmt.Errorf("merkle root of %v doesn't match the jetton master", c.jettonMaster.ToRaw())
+		case merkleRootUnknown:
+			return fmt.Errorf("merkle root of %v is not verified yet", c.jettonMaster.ToRaw())
+		}
+	}
+	return nil
+}
+
+func (h *Handler) checkBackend(ctx context.Context) error {
+	if p, ok := h.backend.(pinger); ok {
+		return p.Ping(ctx)
+	}
+	return nil
+}
+
+// checkEmulator checks that the emulator derives a jetton wallet for every campaign.
+// It runs an emulation per campaign, so its result is reused for readinessEmulatorTTL.
+func (h *Handler) checkEmulator(ctx context.Context) error {
+	return h.emulatorCheck.run(ctx, readinessEmulatorTTL, h.emulateCanaries)
+}
+
+func (h *Handler) emulateCanaries(ctx context.Context) error {
+	for _, c := range h.campaigns {
+		wallet, err := h.emulateJettonWallet(ctx, c.jettonMaster, readinessCanaryOwner)
+		if err != nil {
+			return fmt.Errorf("failed to emulate jetton wallet of %v: %w", c.jettonMaster.ToRaw(), err)
+		}
+		// an empty jetton master state doesn't fail the emulator, it derives the zero address.
+		if wallet == (ton.AccountID{}) {
+			return fmt.Errorf("emulator derived the zero jetton wallet of %v", c.jettonMaster.ToRaw())
+		}
+	}
+	return nil
+}
+
+func (h *Handler) checkQueues(ctx context.Context) error {
+	if h.readinessQueueThreshold <= 0 {
+		return nil
+	}
+	for _, c := range h.campaigns {
+		if length := c.prover.QueueLength(); length >= h.readinessQueueThreshold {
+			return fmt.Errorf("queue of %v has %v requests", c.jettonMaster.ToRaw(), length)
+		}
+	}
+	return nil
+}
+
+// readyzHandler responds with 200 once all readiness checks pass and with 503 otherwise.
+func readyzHandler(h *Handler) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		result := h.readiness(r.Context())
+		w.Header().Set("Content-Type", "application/json")
+		if result.Status != readinessOK {
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+		if err := json.NewEncoder(w).Encode(result); err != nil {
+			h.logger.Warn("failed to write readiness", zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,92 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tonkeeper/tongo/ton"
+	"go.uber.org/zap"
+)
+
+func TestHandler_readiness(t *testing.T) {
+	c, err := newCampaign(zap.NewNop(), CampaignConfig{AirdropFilename: "../prover/testdata/airdropData.boc"}, 1)
+	require.Nil(t, err)
+	server := &mockLiteserver{MemoryBackend: NewMemoryBackend()}
+	h := &Handler{
+		logger:                  zap.NewNop(),
+		campaigns:               map[ton.AccountID]*campaign{c.jettonMaster: c},
+		backend:                 newMockLiteserverPool(server),
+		jettonMasterStateCache:  map[ton.AccountID][2]string{},
+		walletTemplates:         map[ton.AccountID]*walletTemplate{},
+		emulatorPoolSize:        1,
+		readinessQueueThreshold: 10,
+	}
+
+	checks := func() map[string]string {
+		statuses := map[string]string{}
+		for name, check := range h.readiness(context.Background()).Checks {
+			statuses[name] = check.Status
+		}
+		return statuses
+	}
+	// the jetton master isn't deployed, so the emulator check never passes.
+	require.Equal(t, map[string]string{
+		"dictionary":  readinessFail,
+		"merkle_root": readinessFail,
+		"backend":     readinessOK,
+		"emulator":    readinessFail,
+		"queue":       readinessOK,
+	}, checks())
+
+	c.setMerkleRootStatus(merkleRootMismatch)
+	require.Equal(t, readinessFail, checks()["merkle_root"])
+	c.setMerkleRootStatus(merkleRootMatch)
+	require.Equal(t, readinessOK, checks()["merkle_root"])
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.prover.Run(ctx)
+	require.Eventually(t, func() bool {
+		return checks()["dictionary"] == readinessOK
+	}, time.Second, 10*time.Millisecond)
+
+	server.setDown(true)
+	require.Equal(t, readinessFail, checks()["backend"])
+
+	rec := httptest.NewRecorder()
+	readyzHandler(h).ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/readyz", nil))
+	require.Equal(t, http.StatusServiceUnavailable, rec.Code)
+	var body readiness
+	require.Nil(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Equal(t, readinessFail, body.Status)
+	require.Equal(t, readinessOK, body.Checks["dictionary"].Status)
+	require.NotEmpty(t, body.Checks["backend"].Error)
+}
+
+func Test_cachedCheck(t *testing.T) {
+	var c cachedCheck
+	runs := 0
+	check := func(ctx context.Context) error {
+		runs++
+		return errors.New("failed")
+	}
+	require.NotNil(t, c.run(context.Background(), time.Hour, check))
+	require.NotNil(t, c.run(context.Background(), time.Hour, check))
+	require.Equal(t, 1, runs)
+
+	require.NotNil(t, c.run(context.Background(), 0, check))
+	require.Equal(t, 2, runs)
+
+	// a probe that ran out of time isn't cached.
+	var timedOut cachedCheck
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	require.NotNil(t, timedOut.run(ctx, time.Hour, check))
+	require.Nil(t, timedOut.run(context.Background(), time.Hour, func(ctx context.Context) error { return nil }))
+}
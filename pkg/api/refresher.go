@@ -0,0 +1,153 @@
+package api
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+	"github.com/tonkeeper/tongo/ton"
+	"go.uber.org/zap"
+)
+
+var (
+	stateChangesMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "claim_api_state_changes_total",
+		Help: "Number of detected changes of jetton master states and blockchain config",
+	}, []string{"kind"})
+	stateRefreshErrorsMetric = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "claim_api_state_refresh_errors_total",
+		Help: "Number of failed attempts to refresh jetton master states and blockchain config",
+	}, []string{"kind"})
+)
+
+// fetchJettonMasterState returns code and data of a jetton master and its last transaction LT.
+func (h *Handler) fetchJettonMasterState(ctx context.Context, id ton.AccountID) ([2]string, uint64, error) {
+	if h.backend == nil {
+		return [2]string{}, 0, errOffline
+	}
+	account, err := h.backend.GetAccountState(ctx, id)
+	if err != nil {
+		return [2]string{}, 0, err
+	}
+	data := account.Account.Account.Storage.State.AccountActive.StateInit.Data.Value.Value
+	code := account.Account.Account.Storage.State.AccountActive.StateInit.Code.Value.Value
+	d, err := data.ToBocBase64()
+	if err != nil {
+		return [2]string{}, 0, err
+	}
+	c, err := code.ToBocBase64()
+	if err != nil {
+		return [2]string{}, 0, err
+	}
+	return [2]string{c, d}, account.LastTransLt, nil
+}
+
+// refreshCursor is what the refresher has seen so far,
+// an empty cursor makes the config and every jetton master state fetched again.
+type refreshCursor struct {
+	// configKeyBlock is the last key block at the time the config was fetched, valid if configFetched is set.
+	configKeyBlock uint32
+	configFetched  bool
+	lastLT         map[ton.AccountID]uint64
+}
+
+func newRefreshCursor() *refreshCursor {
+	return &refreshCursor{lastLT: map[ton.AccountID]uint64{}}
+}
+
+// runStateRefresher periodically checks whether jetton masters or the blockchain config have changed.
+// Every change replaces the cached state, so emulators never run stale code.
+func (h *Handler) runStateRefresher(ctx context.Context, interval time.Duration) {
+	cursor := newRefreshCursor()
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := h.refreshState(ctx, cursor); err != nil {
+				h.logger.Error("failed to refresh state", zap.Error(err))
+			}
+		}
+	}
+}
+
+func (h *Handler) refreshState(ctx context.Context, cursor *refreshCursor) error {
+	var errs []error
+	if err := h.refreshConfig(ctx, cursor); err != nil {
+		stateRefreshErrorsMetric.WithLabelValues("config").Inc()
+		errs = append(errs, fmt.Errorf("failed to refresh config: %w", err))
+	}
+	for id := range h.campaigns {
+		if err := h.refreshJettonMasterState(ctx, id, cursor.lastLT); err != nil {
+			stateRefreshErrorsMetric.WithLabelValues("jetton_master").Inc()
+			errs = append(errs, fmt.Errorf("failed to refresh jetton master %v: %w", id.ToRaw(), err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// refreshConfig fetches the config only after a new key block, if the backend reports key blocks,
+// because the whole config is large and parsing it by libemulator isn't cheap.
+func (h *Handler) refreshConfig(ctx context.Context, cursor *refreshCursor) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	source, hasKeyBlocks := h.backend.(keyBlockSource)
+	var keyBlock uint32
+	if hasKeyBlocks {
+		var err error
+		keyBlock, err = source.LastKeyBlockSeqno(ctx)
+		if err != nil {
+			return err
+		}
+		if cursor.configFetched && cursor.configKeyBlock == keyBlock {
+			return nil
+		}
+	}
+	config, err := getConfig(ctx, h.backend)
+	if err != nil {
+		return err
+	}
+	h.mu.RLock()
+	changed := config != h.config
+	h.mu.RUnlock()
+	if changed {
+		if err := h.setConfig(config); err != nil {
+			return err
+		}
+		stateChangesMetric.WithLabelValues("config").Inc()
+		h.logger.Info("blockchain config has changed", zap.Uint32("key_block", keyBlock))
+	}
+	cursor.configKeyBlock, cursor.configFetched = keyBlock, hasKeyBlocks
+	return nil
+}
+
+func (h *Handler) refreshJettonMasterState(ctx context.Context, id ton.AccountID, lastLT map[ton.AccountID]uint64) error {
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
+	defer cancel()
+
+	state, lt, err := h.fetchJettonMasterState(ctx, id)
+	if err != nil {
+		return err
+	}
+	if lastLT[id] == lt {
+		return nil
+	}
+	lastLT[id] = lt
+	current, ok := h.jettonMasterState(id)
+	h.setJettonMasterState(id, state)
+	if !ok || current == state {
+		// the state hasn't been used yet or a transaction didn't change code and data, for example an incoming transfer.
+		return nil
+	}
+	stateChangesMetric.WithLabelValues("jetton_master").Inc()
+	h.logger.Info("jetton master state has changed",
+		zap.String("jetton_master", id.ToRaw()),
+		zap.Uint64("last_transaction_lt", lt))
+	return nil
+}
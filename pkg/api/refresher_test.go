@@ -0,0 +1,133 @@
+package api
+
+import (
+	"context"
+	"sync/atomic"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"github.com/tonkeeper/tongo/boc"
+	"github.com/tonkeeper/tongo/liteapi"
+	"github.com/tonkeeper/tongo/tlb"
+	"github.com/tonkeeper/tongo/ton"
+	"go.uber.org/zap"
+)
+
+// countingBackend counts requests of the whole blockchain config.
+type countingBackend struct {
+	*MemoryBackend
+	configRequests atomic.Int32
+}
+
+func (b *countingBackend) GetConfigAll(ctx context.Context, mode liteapi.ConfigMode) (tlb.ConfigParams, error) {
+	b.configRequests.Add(1)
+	return b.MemoryBackend.GetConfigAll(ctx, mode)
+}
+
+// otherBlockchainConfig returns the test config without the config of a bridge.
+func otherBlockchainConfig(t *testing.T) string {
+	cells, err := boc.DeserializeBocBase64(testBlockchainConfig(t))
+	require.Nil(t, err)
+	var params tlb.ConfigParams
+	require.Nil(t, tlb.Unmarshal(cells[0], &params.Config))
+	var keys []uint32
+	for _, key := range params.Config.Keys() {
+		if key != 79 {
+			keys = append(keys, uint32(key))
+		}
+	}
+	params = params.CloneKeepingSubsetOfKeys(keys)
+	cell := boc.NewCell()
+	require.Nil(t, tlb.Marshal(cell, params.Config))
+	config, err := cell.ToBocBase64()
+	require.Nil(t, err)
+	return config
+}
+
+func TestHandler_refreshState(t *testing.T) {
+	_, jettonMaster := newSnapshotHandler(t)
+	code, data := snapshotMasterCells(t, jettonMaster)
+	backend := &countingBackend{MemoryBackend: NewMemoryBackend()}
+	backend.SetAccount(jettonMaster, code, data, 1)
+	require.Nil(t, backend.SetConfig(testBlockchainConfig(t)))
+	h := &Handler{
+		logger:                 zap.NewNop(),
+		campaigns:              map[ton.AccountID]*campaign{jettonMaster: {jettonMaster: jettonMaster}},
+		backend:                backend,
+		libraries:              backend,
+		jettonMasterStateCache: map[ton.AccountID][2]string{},
+		walletTemplates:        map[ton.AccountID]*walletTemplate{},
+	}
+	require.Nil(t, h.setConfig(testBlockchainConfig(t)))
+	ctx := context.Background()
+	changes := func(kind string) float64 {
+		return testutil.ToFloat64(stateChangesMetric.WithLabelValues(kind))
+	}
+	jettonMasterChanges, configChanges := changes("jetton_master"), changes("config")
+
+	cursor := newRefreshCursor()
+	require.Nil(t, h.refreshState(ctx, cursor))
+	require.Equal(t, int32(1), backend.configRequests.Load())
+	_, ok := h.walletTemplate(ctx, jettonMaster)
+	require.True(t, ok)
+	pool, err := h.emulatorPool(ctx, jettonMaster)
+	require.Nil(t, err)
+
+	// an incoming transfer changes the LT only, the config isn't fetched without a new key block.
+	backend.SetAccount(jettonMaster, code, data, 2)
+	require.Nil(t, h.refreshState(ctx, cursor))
+	require.Equal(t, int32(1), backend.configRequests.Load())
+	require.Equal(t, jettonMasterChanges, changes("jetton_master"))
+	current, err := h.emulatorPool(ctx, jettonMaster)
+	require.Nil(t, err)
+	require.Same(t, pool, current)
+	require.NotNil(t, h.walletTemplates[jettonMaster])
+
+	// an upgrade of the jetton master drops everything built from its state.
+	otherCode := boc.NewCell()
+	require.Nil(t, otherCode.WriteUint(1, 8))
+	backend.SetAccount(jettonMaster, otherCode, data, 3)
+	require.Nil(t, h.refreshState(ctx, cursor))
+	require.Equal(t, jettonMasterChanges+1, changes("jetton_master"))
+	h.mu.RLock()
+	_, poolOk := h.emulatorPools[jettonMaster]
+	_, templateOk := h.walletTemplates[jettonMaster]
+	h.mu.RUnlock()
+	require.False(t, poolOk)
+	require.False(t, templateOk)
+	state, _ := h.jettonMasterState(jettonMaster)
+	otherCodeBoc, err := otherCode.ToBocBase64()
+	require.Nil(t, err)
+	require.Equal(t, otherCodeBoc, state[0])
+
+	// a key block with the same config is fetched, but nothing is replaced.
+	emulatorConfig := h.emulatorConfig
+	require.Nil(t, backend.SetConfig(testBlockchainConfig(t)))
+	require.Nil(t, h.refreshState(ctx, cursor))
+	require.Equal(t, int32(2), backend.configRequests.Load())
+	require.Equal(t, configChanges, changes("config"))
+	require.Same(t, emulatorConfig, h.emulatorConfig)
+
+	require.Nil(t, backend.SetConfig(otherBlockchainConfig(t)))
+	require.Nil(t, h.refreshState(ctx, cursor))
+	require.Equal(t, int32(3), backend.configRequests.Load())
+	require.Equal(t, configChanges+1, changes("config"))
+	require.NotSame(t, emulatorConfig, h.emulatorConfig)
+	require.Equal(t, otherBlockchainConfig(t), h.config)
+
+	// an empty cursor fetches the config again.
+	require.Nil(t, h.refreshState(ctx, newRefreshCursor()))
+	require.Equal(t, int32(4), backend.configRequests.Load())
+}
+
+func TestLiteserverPool_LastKeyBlockSeqno(t *testing.T) {
+	server := &mockLiteserver{MemoryBackend: NewMemoryBackend()}
+	p := newMockLiteserverPool(server)
+	require.Nil(t, server.SetConfig(testBlockchainConfig(t)))
+	seqno, err := p.LastKeyBlockSeqno(context.Background())
+	require.Nil(t, err)
+	require.Equal(t, uint32(1), seqno)
+	require.Equal(t, uint32(7), lastKeyBlockSeqno(7, true, 3))
+	require.Equal(t, uint32(3), lastKeyBlockSeqno(8, false, 3))
+}
@@ -1,6 +1,7 @@
 package api
 
 import (
+	"context"
 	"errors"
 	"net/http"
 
@@ -17,6 +18,9 @@ type Server struct {
 
 func NewServer(log *zap.Logger, handler *Handler, address string) (*Server, error) {
 	ogenMiddlewares := []oas.Middleware{ogenLoggingMiddleware(log), ogenMetricsMiddleware}
+	if handler.requestTimeout > 0 {
+		ogenMiddlewares = append(ogenMiddlewares, ogenTimeoutMiddleware(handler.requestTimeout))
+	}
 	ogenServer, err := oas.NewServer(handler,
 		oas.WithMiddleware(ogenMiddlewares...))
 
@@ -24,8 +28,9 @@ func NewServer(log *zap.Logger, handler *Handler, address string) (*Server, erro
 		return nil, err
 	}
 	mux := http.NewServeMux()
-	mux.Handle("/", ogenServer)
+	mux.Handle("/", retryAfterMiddleware(ogenServer))
 	mux.HandleFunc("/healthz", healthzHandler())
+	mux.HandleFunc("/readyz", readyzHandler(handler))
 
 	serv := Server{
 		logger: log,
@@ -37,13 +42,19 @@ func NewServer(log *zap.Logger, handler *Handler, address string) (*Server, erro
 	return &serv, nil
 }
 
-func (s *Server) Run() {
+// Run serves requests until Shutdown is called.
+func (s *Server) Run() error {
 	err := s.httpServer.ListenAndServe()
 	if errors.Is(err, http.ErrServerClosed) {
 		s.logger.Info("claim-api-go quit")
-		return
+		return nil
 	}
-	s.logger.Fatal("ListedAndServe() failed", zap.Error(err))
+	return err
+}
+
+// Shutdown stops accepting connections and waits for in-flight requests to finish.
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.httpServer.Shutdown(ctx)
 }
 
 func healthzHandler() http.HandlerFunc {
@@ -0,0 +1,209 @@
+package api
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/tonkeeper/tongo/boc"
+	"github.com/tonkeeper/tongo/ton"
+	"go.uber.org/zap"
+)
+
+// errOffline is returned for blockchain requests that can't be served from a snapshot.
+var errOffline = errors.New("not available in offline mode")
+
+// libraryResolver resolves library cells for emulators.
+type libraryResolver interface {
+	GetLibraries(ctx context.Context, libraryList []ton.Bits256) (map[ton.Bits256]*boc.Cell, error)
+}
+
+// Snapshot is a pinned state of the blockchain, which is enough to serve claims without a network.
+// It is stored as a JSON file, all cells are base64 encoded BOCs.
+type Snapshot struct {
+	CreatedAt int64 `json:"created_at"`
+	// Config is a blockchain config trimmed the same way as getConfig does.
+	Config        string            `json:"config"`
+	JettonMasters []SnapshotAccount `json:"jetton_masters"`
+	Libraries     []SnapshotLibrary `json:"libraries"`
+	libraries     map[ton.Bits256]*boc.Cell
+}
+
+type SnapshotAccount struct {
+	Account           string `json:"account"`
+	Code              string `json:"code"`
+	Data              string `json:"data"`
+	LastTransactionLt uint64 `json:"last_transaction_lt"`
+}
+
+type SnapshotLibrary struct {
+	Hash string `json:"hash"`
+	Cell string `json:"cell"`
+}
+
+// CreateSnapshot captures states of the given jetton masters, the blockchain config
+// and library cells used by get-methods of the jetton masters.
+func CreateSnapshot(ctx context.Context, logger *zap.Logger, backend Backend, jettonMasters []ton.AccountID) (*Snapshot, error) {
+	config, err := getConfig(ctx, backend)
+	if err != nil {
+		return nil, err
+	}
+	recorder := &recordingResolver{resolver: backend, libraries: map[ton.Bits256]*boc.Cell{}}
+	h := &Handler{
+		logger:                 logger,
+		backend:                backend,
+		libraries:              recorder,
+		jettonMasterStateCache: map[ton.AccountID][2]string{},
+		walletTemplates:        map[ton.AccountID]*walletTemplate{},
+	}
+	if err := h.setConfig(config); err != nil {
+		return nil, err
+	}
+	snapshot := &Snapshot{CreatedAt: time.Now().Unix(), Config: config}
+	for _, jettonMaster := range jettonMasters {
+		state, lt, err := h.fetchJettonMasterState(ctx, jettonMaster)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get state of %v: %w", jettonMaster.ToRaw(), err)
+		}
+		h.setJettonMasterState(jettonMaster, state)
+		// run every get-method the handler uses, so that all required libraries are recorded.
+		if _, err := h.getMerkleRoot(ctx, jettonMaster); err != nil {
+			return nil, fmt.Errorf("failed to get merkle root of %v: %w", jettonMaster.ToRaw(), err)
+		}
+		if _, err := h.resolveWalletTemplate(ctx, jettonMaster); err != nil {
+			return nil, fmt.Errorf("failed to get jetton wallets of %v: %w", jettonMaster.ToRaw(), err)
+		}
+		snapshot.JettonMasters = append(snapshot.JettonMasters, SnapshotAccount{
+			Account:           jettonMaster.ToRaw(),
+			Code:              state[0],
+			Data:              state[1],
+			LastTransactionLt: lt,
+		})
+	}
+	for hash, cell := range recorder.libraries {
+		content, err := cell.ToBocBase64()
+		if err != nil {
+			return nil, err
+		}
+		snapshot.Libraries = append(snapshot.Libraries, SnapshotLibrary{Hash: hash.Hex(), Cell: content})
+	}
+	snapshot.libraries = recorder.libraries
+	return snapshot, nil
+}
+
+// ReadSnapshot reads a snapshot written by Snapshot.Write.
+func ReadSnapshot(filename string) (*Snapshot, error) {
+	content, err := os.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	var snapshot Snapshot
+	if err := json.Unmarshal(content, &snapshot); err != nil {
+		return nil, fmt.Errorf("invalid snapshot %v: %w", filename, err)
+	}
+	snapshot.libraries = make(map[ton.Bits256]*boc.Cell, len(snapshot.Libraries))
+	for _, library := range snapshot.Libraries {
+		hash, err := ton.ParseHash(library.Hash)
+		if err != nil {
+			return nil, fmt.Errorf("invalid library hash %v: %w", library.Hash, err)
+		}
+		cells, err := boc.DeserializeBocBase64(library.Cell)
+		if err != nil || len(cells) != 1 {
+			return nil, fmt.Errorf("invalid library %v", library.Hash)
+		}
+		snapshot.libraries[hash] = cells[0]
+	}
+	return &snapshot, nil
+}
+
+func (s *Snapshot) Write(filename string) error {
+	content, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	tmp, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+	if _, err := tmp.Write(content); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filename)
+}
+
+// jettonMasterState returns the pinned state of the given jetton master.
+func (s *Snapshot) jettonMasterState(jettonMaster ton.AccountID) ([2]string, bool) {
+	for _, account := range s.JettonMasters {
+		id, err := ton.ParseAccountID(account.Account)
+		if err == nil && id == jettonMaster {
+			return [2]string{account.Code, account.Data}, true
+		}
+	}
+	return [2]string{}, false
+}
+
+func (s *Snapshot) GetLibraries(ctx context.Context, libraryList []ton.Bits256) (map[ton.Bits256]*boc.Cell, error) {
+	libraries := make(map[ton.Bits256]*boc.Cell, len(libraryList))
+	for _, hash := range libraryList {
+		cell, ok := s.libraries[hash]
+		if !ok {
+			return nil, fmt.Errorf("library %v is not in the snapshot", hash.Hex())
+		}
+		libraries[hash] = cell
+	}
+	return libraries, nil
+}
+
+// recordingResolver remembers all libraries resolved through it.
+type recordingResolver struct {
+	resolver libraryResolver
+
+	mu        sync.Mutex
+	libraries map[ton.Bits256]*boc.Cell
+}
+
+func (r *recordingResolver) GetLibraries(ctx context.Context, libraryList []ton.Bits256) (map[ton.Bits256]*boc.Cell, error) {
+	libraries, err := r.resolver.GetLibraries(ctx, libraryList)
+	if err != nil {
+		return nil, err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for hash, cell := range libraries {
+		r.libraries[hash] = cell
+	}
+	return libraries, nil
+}
+
+// loadSnapshot makes the handler serve the pinned state from the given snapshot.
+func (h *Handler) loadSnapshot(filename string) error {
+	snapshot, err := ReadSnapshot(filename)
+	if err != nil {
+		return err
+	}
+	h.logger.Info("running from snapshot",
+		zap.String("filename", filename),
+		zap.Time("created_at", time.Unix(snapshot.CreatedAt, 0)))
+	h.libraries = snapshot
+	if err := h.setConfig(snapshot.Config); err != nil {
+		return err
+	}
+	for id := range h.campaigns {
+		state, ok := snapshot.jettonMasterState(id)
+		if !ok {
+			return fmt.Errorf("jetton master %v is not in the snapshot %v", id.ToRaw(), filename)
+		}
+		h.setJettonMasterState(id, state)
+	}
+	return nil
+}
@@ -0,0 +1,106 @@
+package api
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tonkeeper/tongo/boc"
+	"github.com/tonkeeper/tongo/ton"
+	"go.uber.org/zap"
+)
+
+func TestSnapshot(t *testing.T) {
+	empty := emptyCellBoc(t)
+	jettonMaster := ton.MustParseAccountID("EQD6Z9DHc5Mx-8PI8I4BjGX0d2NhapaRAK12CgstweNoMint")
+	library := boc.NewCell()
+	require.Nil(t, library.WriteUint(42, 32))
+	hash, err := library.Hash256()
+	require.Nil(t, err)
+	content, err := library.ToBocBase64()
+	require.Nil(t, err)
+
+	snapshot := &Snapshot{
+		CreatedAt:     1700000000,
+		Config:        empty,
+		JettonMasters: []SnapshotAccount{{Account: jettonMaster.ToRaw(), Code: empty, Data: empty, LastTransactionLt: 10}},
+		Libraries:     []SnapshotLibrary{{Hash: ton.Bits256(hash).Hex(), Cell: content}},
+	}
+	filename := filepath.Join(t.TempDir(), "snapshot.json")
+	require.Nil(t, snapshot.Write(filename))
+
+	loaded, err := ReadSnapshot(filename)
+	require.Nil(t, err)
+	require.Equal(t, snapshot.JettonMasters, loaded.JettonMasters)
+	require.Equal(t, snapshot.Config, loaded.Config)
+
+	libraries, err := loaded.GetLibraries(context.Background(), []ton.Bits256{hash})
+	require.Nil(t, err)
+	require.Equal(t, 1, len(libraries))
+	value, err := libraries[hash].ReadUint(32)
+	require.Nil(t, err)
+	require.Equal(t, uint64(42), value)
+	_, err = loaded.GetLibraries(context.Background(), []ton.Bits256{{1}})
+	require.NotNil(t, err)
+
+	state, ok := loaded.jettonMasterState(jettonMaster)
+	require.True(t, ok)
+	require.Equal(t, [2]string{empty, empty}, state)
+}
+
+func TestHandler_loadSnapshot(t *testing.T) {
+	empty := emptyCellBoc(t)
+	jettonMaster := ton.MustParseAccountID("EQD6Z9DHc5Mx-8PI8I4BjGX0d2NhapaRAK12CgstweNoMint")
+	snapshot := &Snapshot{
+		Config:        testBlockchainConfig(t),
+		JettonMasters: []SnapshotAccount{{Account: jettonMaster.ToRaw(), Code: empty, Data: empty}},
+	}
+	filename := filepath.Join(t.TempDir(), "snapshot.json")
+	require.Nil(t, snapshot.Write(filename))
+
+	h := &Handler{
+		logger:                 zap.NewNop(),
+		campaigns:              map[ton.AccountID]*campaign{jettonMaster: {jettonMaster: jettonMaster}},
+		jettonMasterStateCache: map[ton.AccountID][2]string{},
+		walletTemplates:        map[ton.AccountID]*walletTemplate{},
+	}
+	require.Nil(t, h.loadSnapshot(filename))
+	state, ok := h.jettonMasterState(jettonMaster)
+	require.True(t, ok)
+	require.Equal(t, [2]string{empty, empty}, state)
+
+	// emulators are built from the snapshot without a network.
+	_, release, err := h.executor(context.Background(), jettonMaster)
+	require.Nil(t, err)
+	release()
+	_, _, err = h.fetchJettonMasterState(context.Background(), jettonMaster)
+	require.ErrorIs(t, err, errOffline)
+
+	missing := ton.MustParseAccountID("0:ff41b315c634b4ea4814b9262499567d36e9c7b13da09476f11a41d94e2cb700")
+	h.campaigns[missing] = &campaign{jettonMaster: missing}
+	require.ErrorContains(t, h.loadSnapshot(filename), "is not in the snapshot")
+}
+
+// testBlockchainConfig returns a real blockchain config, libemulator aborts on an invalid one.
+func testBlockchainConfig(t *testing.T) string {
+	content, err := os.ReadFile("testdata/config.txt")
+	require.Nil(t, err)
+	return strings.TrimSpace(string(content))
+}
+
+// newSnapshotHandler returns a handler serving the jetton master pinned in testdata/snapshot.json.
+// The merkle root of the jetton master is the one of prover/testdata/airdropData.boc.
+func newSnapshotHandler(t *testing.T) (*Handler, ton.AccountID) {
+	jettonMaster := ton.MustParseAccountID("EQD6Z9DHc5Mx-8PI8I4BjGX0d2NhapaRAK12CgstweNoMint")
+	h := &Handler{
+		logger:                 zap.NewNop(),
+		campaigns:              map[ton.AccountID]*campaign{jettonMaster: {jettonMaster: jettonMaster}},
+		jettonMasterStateCache: map[ton.AccountID][2]string{},
+		walletTemplates:        map[ton.AccountID]*walletTemplate{},
+	}
+	require.Nil(t, h.loadSnapshot("testdata/snapshot.json"))
+	return h, jettonMaster
+}
@@ -0,0 +1,136 @@
+package api
+
+import (
+	"context"
+	"math"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+
+	"github.com/tonkeeper/claim-api-go/pkg/api/oas"
+	"github.com/tonkeeper/claim-api-go/pkg/prover"
+)
+
+var (
+	campaignRecipientsMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "claim_api_campaign_recipients",
+		Help: "Number of recipients in the airdrop dictionary",
+	}, []string{"jetton_master"})
+	campaignAmountMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "claim_api_campaign_amount",
+		Help: "Total, min, max and median amounts of the airdrop dictionary, see /stats for exact values",
+	}, []string{"jetton_master", "stat"})
+	campaignRecipientsByTimeMetric = promauto.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "claim_api_campaign_recipients_by_time",
+		Help: "Number of recipients whose start_from or expired_at falls into the given period from now: past, 1d, 7d, 30d or later",
+	}, []string{"jetton_master", "field", "period"})
+)
+
+// statsMetricsInterval is how often periods of recipients are recomputed, as they are relative to now.
+const statsMetricsInterval = time.Hour
+
+// recipientsPeriod is a fixed bucket of recipientsByTime, so the number of series doesn't depend on a dictionary.
+type recipientsPeriod struct {
+	name string
+	// until is the end of the period from now, the previous period is its beginning.
+	until time.Duration
+}
+
+var recipientsPeriods = []recipientsPeriod{
+	{name: "past", until: 0},
+	{name: "1d", until: 24 * time.Hour},
+	{name: "7d", until: 7 * 24 * time.Hour},
+	{name: "30d", until: 30 * 24 * time.Hour},
+	{name: "later", until: math.MaxInt64},
+}
+
+func (h *Handler) GetStats(ctx context.Context) (*oas.CampaignStats, error) {
+	return convertStats(h.defaultCampaign), nil
+}
+
+func (h *Handler) GetCampaignStats(ctx context.Context, params oas.GetCampaignStatsParams) (*oas.CampaignStats, error) {
+	c, err := h.campaign(params.JettonMaster)
+	if err != nil {
+		return nil, err
+	}
+	return convertStats(c), nil
+}
+
+func convertStats(c *campaign) *oas.CampaignStats {
+	stats := c.prover.Stats()
+	return &oas.CampaignStats{
+		JettonMaster: c.jettonMaster.ToRaw(),
+		MerkleRoot:   c.prover.MerkleRoot().Hex(),
+		Recipients:   int64(stats.Recipients),
+		TotalAmount:  stats.TotalAmount.String(),
+		MinAmount:    strconv.FormatUint(uint64(stats.MinAmount), 10),
+		MaxAmount:    strconv.FormatUint(uint64(stats.MaxAmount), 10),
+		MedianAmount: strconv.FormatUint(uint64(stats.MedianAmount), 10),
+		StartFrom:    convertHistogram(stats.StartFrom),
+		ExpiredAt:    convertHistogram(stats.ExpireAt),
+	}
+}
+
+func convertHistogram(buckets []prover.HistogramBucket) oas.TimeHistogram {
+	histogram := make(oas.TimeHistogram, 0, len(buckets))
+	for _, bucket := range buckets {
+		histogram = append(histogram, oas.TimeHistogramItem{
+			From:  bucket.From,
+			Count: int64(bucket.Count),
+		})
+	}
+	return histogram
+}
+
+// updateStatsMetrics exports statistics of the current airdrop dictionary of the campaign.
+func (c *campaign) updateStatsMetrics() {
+	jettonMaster := c.jettonMaster.ToRaw()
+	stats := c.prover.Stats()
+	campaignRecipientsMetric.WithLabelValues(jettonMaster).Set(float64(stats.Recipients))
+	total, _ := stats.TotalAmount.Float64()
+	campaignAmountMetric.WithLabelValues(jettonMaster, "total").Set(total)
+	campaignAmountMetric.WithLabelValues(jettonMaster, "min").Set(float64(stats.MinAmount))
+	campaignAmountMetric.WithLabelValues(jettonMaster, "max").Set(float64(stats.MaxAmount))
+	campaignAmountMetric.WithLabelValues(jettonMaster, "median").Set(float64(stats.MedianAmount))
+
+	for field, buckets := range map[string][]prover.HistogramBucket{"start_from": stats.StartFrom, "expired_at": stats.ExpireAt} {
+		counts := recipientsByTime(buckets, time.Now())
+		for _, period := range recipientsPeriods {
+			campaignRecipientsByTimeMetric.WithLabelValues(jettonMaster, field, period.name).Set(float64(counts[period.name]))
+		}
+	}
+}
+
+// recipientsByTime splits a histogram into recipientsPeriods.
+// A daily bucket is counted by its beginning, so the precision is the same as in /stats.
+func recipientsByTime(buckets []prover.HistogramBucket, now time.Time) map[string]int {
+	counts := make(map[string]int, len(recipientsPeriods))
+	for _, bucket := range buckets {
+		from := time.Unix(bucket.From, 0)
+		for _, period := range recipientsPeriods {
+			if period.until == math.MaxInt64 || from.Before(now.Add(period.until)) {
+				counts[period.name] += bucket.Count
+				break
+			}
+		}
+	}
+	return counts
+}
+
+// runStatsMetrics keeps periods of recipients relative to the current time.
+func (h *Handler) runStatsMetrics(ctx context.Context) {
+	ticker := time.NewTicker(statsMetricsInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for _, c := range h.campaigns {
+				c.updateStatsMetrics()
+			}
+		}
+	}
+}
@@ -0,0 +1,41 @@
+package api
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/tonkeeper/claim-api-go/pkg/prover"
+)
+
+func Test_recipientsByTime(t *testing.T) {
+	now := time.Date(2026, 10, 17, 12, 0, 0, 0, time.UTC)
+	day := func(days int) int64 {
+		return now.Truncate(24*time.Hour).AddDate(0, 0, days).Unix()
+	}
+	buckets := []prover.HistogramBucket{
+		{From: day(-100), Count: 1},
+		{From: day(0), Count: 2},
+		{From: day(1), Count: 3},
+		{From: day(5), Count: 4},
+		{From: day(20), Count: 5},
+		{From: day(400), Count: 6},
+	}
+	require.Equal(t, map[string]int{"past": 3, "1d": 3, "7d": 4, "30d": 5, "later": 6}, recipientsByTime(buckets, now))
+}
+
+func TestCampaign_updateStatsMetrics(t *testing.T) {
+	c, err := newCampaign(zap.NewNop(), CampaignConfig{AirdropFilename: "../prover/testdata/airdropData.boc"}, 1)
+	require.Nil(t, err)
+	jettonMaster := c.jettonMaster.ToRaw()
+	for _, field := range []string{"start_from", "expired_at"} {
+		total := 0.0
+		for _, period := range recipientsPeriods {
+			total += testutil.ToFloat64(campaignRecipientsByTimeMetric.WithLabelValues(jettonMaster, field, period.name))
+		}
+		require.Equal(t, float64(c.prover.Stats().Recipients), total)
+	}
+}
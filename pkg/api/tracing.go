@@ -0,0 +1,81 @@
+package api
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/tonkeeper/tongo/abi"
+	"github.com/tonkeeper/tongo/tlb"
+	"github.com/tonkeeper/tongo/ton"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer uses the global tracer provider, so spans are dropped until SetupTracing is called.
+var tracer = otel.Tracer("github.com/tonkeeper/claim-api-go/pkg/api")
+
+type TracingConfig struct {
+	// Endpoint is host:port of an OTLP/HTTP collector, empty disables tracing.
+	Endpoint string
+	// Insecure disables TLS of the connection to the collector.
+	Insecure bool
+	// SampleRatio is a fraction of traces to export, traces started by a sampled remote parent are always exported.
+	SampleRatio float64
+	ServiceName string
+}
+
+// SetupTracing installs a global tracer provider exporting spans to an OTLP collector.
+// The returned function flushes the remaining spans, it must be called before the process exits.
+func SetupTracing(ctx context.Context, config TracingConfig) (func(ctx context.Context) error, error) {
+	if config.Endpoint == "" {
+		return func(ctx context.Context) error { return nil }, nil
+	}
+	options := []otlptracehttp.Option{otlptracehttp.WithEndpoint(config.Endpoint)}
+	if config.Insecure {
+		options = append(options, otlptracehttp.WithInsecure())
+	}
+	exporter, err := otlptracehttp.New(ctx, options...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create otlp exporter: %w", err)
+	}
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+		sdktrace.WithResource(resource.NewSchemaless(semconv.ServiceName(config.ServiceName))),
+	)
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	return provider.Shutdown, nil
+}
+
+// endSpan records an error of a span, if any, and ends the span.
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// tracedExecutor records a span for every get method run by an emulator.
+type tracedExecutor struct {
+	abi.Executor
+}
+
+func (e tracedExecutor) RunSmcMethodByID(ctx context.Context, accountID ton.AccountID, methodID int, params tlb.VmStack) (uint32, tlb.VmStack, error) {
+	ctx, span := tracer.Start(ctx, "emulator.RunSmcMethodByID", trace.WithAttributes(
+		attribute.String("account", accountID.ToRaw()),
+		attribute.Int("method_id", methodID),
+	))
+	exitCode, stack, err := e.Executor.RunSmcMethodByID(ctx, accountID, methodID, params)
+	span.SetAttributes(attribute.Int64("exit_code", int64(exitCode)))
+	endSpan(span, err)
+	return exitCode, stack, err
+}
@@ -0,0 +1,115 @@
+package api
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tonkeeper/tongo/tlb"
+	"github.com/tonkeeper/tongo/ton"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.uber.org/zap"
+)
+
+var (
+	recorderOnce sync.Once
+	recorder     *tracetest.SpanRecorder
+)
+
+// recordSpans starts a trace recorded in memory and returns a function listing its ended spans.
+// A global tracer provider can be installed only once, so tests share the recorder.
+func recordSpans(t *testing.T) (context.Context, func() []sdktrace.ReadOnlySpan) {
+	recorderOnce.Do(func() {
+		recorder = tracetest.NewSpanRecorder()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder)))
+	})
+	ctx, root := otel.Tracer("test").Start(context.Background(), t.Name())
+	t.Cleanup(func() { root.End() })
+	return ctx, func() []sdktrace.ReadOnlySpan {
+		var spans []sdktrace.ReadOnlySpan
+		for _, span := range recorder.Ended() {
+			if span.SpanContext().TraceID() == root.SpanContext().TraceID() {
+				spans = append(spans, span)
+			}
+		}
+		return spans
+	}
+}
+
+func spanByName(t *testing.T, spans []sdktrace.ReadOnlySpan, name string) sdktrace.ReadOnlySpan {
+	for _, span := range spans {
+		if span.Name() == name {
+			return span
+		}
+	}
+	require.FailNowf(t, "span is not found", "%v", name)
+	return nil
+}
+
+func TestTracing_prove(t *testing.T) {
+	c, err := newCampaign(zap.NewNop(), CampaignConfig{AirdropFilename: "../prover/testdata/airdropData.boc"}, 1)
+	require.Nil(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go c.prover.Run(ctx)
+	h := &Handler{logger: zap.NewNop()}
+
+	ctx, spans := recordSpans(t)
+	owner := ton.MustParseAccountID("0:050b89727f74efd71e3f5c396c76c6df7ee71aced7c2ec7a8c55bb8bba8d1399")
+	_, err = h.prove(ctx, c, owner)
+	require.Nil(t, err)
+
+	// the context of a request is propagated through the queue to the prover.
+	prove := spanByName(t, spans(), "prove")
+	for _, name := range []string{"queue.wait", "prover.ProofRequest"} {
+		span := spanByName(t, spans(), name)
+		require.Equal(t, prove.SpanContext().SpanID(), span.Parent().SpanID())
+	}
+}
+
+func TestTracing_liteserverPool(t *testing.T) {
+	accountID := ton.MustParseAccountID("0:6ccd325a858c379693fae2bcaab1c2906831a4e10a6c3bb44ee8b615bca1d220")
+	bad := &mockLiteserver{MemoryBackend: NewMemoryBackend(), down: true}
+	good := &mockLiteserver{MemoryBackend: NewMemoryBackend()}
+	p := newMockLiteserverPool(bad, good)
+	p.next.Store(uint32(len(p.servers) - 1))
+
+	ctx, spans := recordSpans(t)
+	_, err := p.GetAccountState(ctx, accountID)
+	require.Nil(t, err)
+
+	// every attempt gets its own span.
+	var statuses []codes.Code
+	for _, span := range spans() {
+		require.Equal(t, "liteserver.GetAccountState", span.Name())
+		statuses = append(statuses, span.Status().Code)
+	}
+	require.Equal(t, []codes.Code{codes.Error, codes.Unset}, statuses)
+}
+
+type failingExecutor struct{}
+
+func (failingExecutor) RunSmcMethodByID(ctx context.Context, accountID ton.AccountID, methodID int, params tlb.VmStack) (uint32, tlb.VmStack, error) {
+	return 11, nil, fmt.Errorf("method execution failed")
+}
+
+func TestTracing_emulator(t *testing.T) {
+	ctx, spans := recordSpans(t)
+	accountID := ton.MustParseAccountID("0:6ccd325a858c379693fae2bcaab1c2906831a4e10a6c3bb44ee8b615bca1d220")
+	_, _, err := tracedExecutor{failingExecutor{}}.RunSmcMethodByID(ctx, accountID, 69258, nil)
+	require.NotNil(t, err)
+
+	span := spanByName(t, spans(), "emulator.RunSmcMethodByID")
+	require.Equal(t, codes.Error, span.Status().Code)
+	attributes := map[string]any{}
+	for _, attribute := range span.Attributes() {
+		attributes[string(attribute.Key)] = attribute.Value.AsInterface()
+	}
+	require.Equal(t, int64(69258), attributes["method_id"])
+	require.Equal(t, int64(11), attributes["exit_code"])
+}
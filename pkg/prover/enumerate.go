@@ -7,6 +7,21 @@ import (
 	"github.com/tonkeeper/tongo/ton"
 )
 
+// detach returns a shallow copy of the given cell with its own read cursors.
+// Cells of a loaded dictionary are shared between workers and must never be read directly,
+// so every cell is detached before it is read.
+func detach(c *boc.Cell) *boc.Cell {
+	detached := boc.NewCellWithBits(c.RawBitString())
+	for _, ref := range c.Refs() {
+		if err := detached.AddRef(ref); err != nil {
+			// this should never happen because a copy has the same number of refs
+			panic(err)
+		}
+	}
+	detached.ResetCounters()
+	return detached
+}
+
 func readCommonPrefix(size int, c *boc.Cell) (int, *boc.BitString, error) {
 	first, err := c.ReadBit()
 	if err != nil {
@@ -162,6 +177,8 @@ type walletData struct {
 	Data      AirdropData
 }
 
+// walk enumerates accounts of a dictionary starting from the given key.
+// The cell must be detached, its children are detached by walk itself.
 func walk(startKey *boc.BitString, prefix *boc.BitString, cell *boc.Cell, count int) ([]walletData, error) {
 	startKey.ResetCounter()
 	prefix.ResetCounter()
@@ -217,22 +234,17 @@ func walk(startKey *boc.BitString, prefix *boc.BitString, cell *boc.Cell, count
 	case 1:
 		return nil, nil
 	}
+	refs := cell.Refs()
+	if len(refs) != 2 {
+		return nil, boc.ErrNotEnoughRefs
+	}
 	var arrLeft []walletData
-	if skipLeft {
-		_, err := cell.NextRef()
-		if err != nil {
-			return nil, err
-		}
-	} else {
+	if !skipLeft {
 		left, err := addBit(currentPrefix, false)
 		if err != nil {
 			return nil, err
 		}
-		leftRef, err := cell.NextRef()
-		if err != nil {
-			return nil, err
-		}
-		arrLeft, err = walk(startKey, left, leftRef, count)
+		arrLeft, err = walk(startKey, left, detach(refs[0]), count)
 		if err != nil {
 			return nil, err
 		}
@@ -244,11 +256,7 @@ func walk(startKey *boc.BitString, prefix *boc.BitString, cell *boc.Cell, count
 	if err != nil {
 		return nil, err
 	}
-	rightRef, err := cell.NextRef()
-	if err != nil {
-		return nil, err
-	}
-	arrRight, err := walk(startKey, right, rightRef, count-len(arrLeft))
+	arrRight, err := walk(startKey, right, detach(refs[1]), count-len(arrLeft))
 	if err != nil {
 		return nil, err
 	}
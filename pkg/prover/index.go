@@ -0,0 +1,35 @@
+package prover
+
+import (
+	"bytes"
+	"slices"
+
+	"github.com/tonkeeper/tongo/ton"
+)
+
+// seekEntries returns up to count entries starting from the given account.
+// Entries must be sorted in the key order of a dictionary, so the start is found with a binary search
+// instead of walking the dictionary from the root.
+// The returned slice shares memory with entries and must not be modified.
+func seekEntries(entries []walletData, nextFrom ton.AccountID, count int) []walletData {
+	start, _ := slices.BinarySearchFunc(entries, nextFrom, func(entry walletData, accountID ton.AccountID) int {
+		return compareAccountIDs(entry.AccountID, accountID)
+	})
+	if count > len(entries)-start {
+		return entries[start:]
+	}
+	return entries[start : start+count]
+}
+
+// compareAccountIDs compares accounts in the order of keys of an airdrop dictionary.
+// A key is a serialized addr_std, so a workchain is compared as an unsigned byte
+// and the masterchain (-1) goes after the basechain (0).
+func compareAccountIDs(a, b ton.AccountID) int {
+	if wa, wb := uint8(int8(a.Workchain)), uint8(int8(b.Workchain)); wa != wb {
+		if wa < wb {
+			return -1
+		}
+		return 1
+	}
+	return bytes.Compare(a.Address[:], b.Address[:])
+}
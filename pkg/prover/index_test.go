@@ -0,0 +1,98 @@
+package prover
+
+import (
+	"crypto/rand"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tonkeeper/tongo/tlb"
+	"github.com/tonkeeper/tongo/ton"
+)
+
+func Test_seekEntries(t *testing.T) {
+	root, _ := readAirdropDataFile(t, "testdata/airdropData.boc")
+	dict, err := loadDictionary("testdata/airdropData.boc")
+	require.Nil(t, err)
+
+	starts := []ton.AccountID{
+		{},
+		ton.MustParseAccountID("0:00fdb15f679957128fd0ee8f740aaca4f37a6877e31d61a454ed9c7604a5c2dc"),
+		ton.MustParseAccountID("0:ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff"),
+		ton.MustParseAccountID("-1:0000000000000000000000000000000000000000000000000000000000000000"),
+	}
+	for _, entry := range dict.entries {
+		starts = append(starts, entry.AccountID)
+	}
+	for _, start := range starts {
+		for _, count := range []int{1, 5, 1000} {
+			root.ResetCounters()
+			expected, err := enumerateAccounts(start, root, count)
+			require.Nil(t, err)
+			entries := seekEntries(dict.entries, start, count)
+			require.Equal(t, len(expected), len(entries), "start %v, count %v", start.ToRaw(), count)
+			if len(expected) > 0 {
+				require.Equal(t, expected, entries)
+			}
+		}
+	}
+}
+
+func Test_compareAccountIDs(t *testing.T) {
+	basechain := ton.MustParseAccountID("0:ffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff")
+	masterchain := ton.MustParseAccountID("-1:0000000000000000000000000000000000000000000000000000000000000000")
+	require.Equal(t, -1, compareAccountIDs(basechain, masterchain))
+	require.Equal(t, 1, compareAccountIDs(masterchain, basechain))
+	require.Equal(t, 0, compareAccountIDs(basechain, basechain))
+
+	a, err := accountIDToBitString(basechain)
+	require.Nil(t, err)
+	b, err := accountIDToBitString(masterchain)
+	require.Nil(t, err)
+	c, err := compareBitStrings(a, b)
+	require.Nil(t, err)
+	require.Equal(t, -1, c)
+}
+
+func generateAirdropDataFile(b *testing.B, size int) string {
+	keys := make([]Address, 0, size)
+	values := make([]AirdropData, 0, size)
+	for i := 0; i < size; i++ {
+		var accountID ton.AccountID
+		_, err := rand.Read(accountID.Address[:])
+		require.Nil(b, err)
+		keys = append(keys, Address{MsgAddress: accountID.ToMsgAddress()})
+		values = append(values, AirdropData{Amount: tlb.Coins(i + 1), StartFrom: 1, ExpireAt: 2})
+	}
+	filename := filepath.Join(b.TempDir(), "airdropData.boc")
+	writeAirdropDataFile(b, filename, tlb.NewHashmap(keys, values))
+	return filename
+}
+
+func BenchmarkEnumerate(b *testing.B) {
+	const size = 100_000
+	const count = 100
+	dict, err := loadDictionary(generateAirdropDataFile(b, size))
+	require.Nil(b, err)
+
+	for _, position := range []int{0, size / 2, size - count} {
+		nextFrom := dict.entries[position].AccountID
+		b.Run(fmt.Sprintf("walk/page-%v", position/count), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := enumerateAccounts(nextFrom, dict.root, count); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+		b.Run(fmt.Sprintf("index/page-%v", position/count), func(b *testing.B) {
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := dict.enumerate(nextFrom, count); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
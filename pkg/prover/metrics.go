@@ -14,4 +14,11 @@ var (
 		},
 		[]string{"method"},
 	)
+	proverExpiredRequestsCounterVec = promauto.NewCounterVec(
+		prometheus.CounterOpts{
+			Name: "claim_api_prover_expired_requests_total",
+			Help: "Number of requests skipped because their context was done before processing",
+		},
+		[]string{"request"},
+	)
 )
@@ -0,0 +1,11 @@
+//go:build !unix
+
+package prover
+
+import "os"
+
+func mmapFile(filename string) ([]byte, error) {
+	return os.ReadFile(filename)
+}
+
+func munmapFile(data []byte) {}
@@ -0,0 +1,30 @@
+//go:build unix
+
+package prover
+
+import (
+	"os"
+	"syscall"
+)
+
+func mmapFile(filename string) ([]byte, error) {
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	info, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if info.Size() == 0 {
+		return nil, nil
+	}
+	return syscall.Mmap(int(f.Fd()), 0, int(info.Size()), syscall.PROT_READ, syscall.MAP_SHARED)
+}
+
+func munmapFile(data []byte) {
+	if len(data) > 0 {
+		_ = syscall.Munmap(data)
+	}
+}
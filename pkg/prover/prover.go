@@ -2,28 +2,77 @@ package prover
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"math"
 	"os"
+	"runtime"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/tonkeeper/tongo/boc"
 	"github.com/tonkeeper/tongo/tlb"
 	"github.com/tonkeeper/tongo/ton"
+	"go.opentelemetry.io/otel/attribute"
 	"go.uber.org/zap"
 
 	"github.com/tonkeeper/claim-api-go/pkg/utils"
 )
 
+// errKeyNotFound is returned when there is no account in a dictionary.
+// Its text is the same as tongo uses for missing keys.
+var errKeyNotFound = errors.New("key is not found")
+
 type ProofResponse struct {
 	WalletAirdrop WalletAirdrop
-	Err           error
+	// MerkleRoot is a merkle root of the dictionary the proof was generated against.
+	MerkleRoot tlb.Bits256
+	Err        error
 }
 
 type ProofRequest struct {
+	// Ctx is the context of the requester, the prover skips the request if it is done
+	// by the time a worker gets to the request. Nil means that the request never expires.
+	Ctx        context.Context
 	AccountID  ton.AccountID
 	ResponseCh chan<- ProofResponse
 }
 
+// Deadline implements utils.Deadliner, so the queue drops a request past the deadline of its context.
+func (r ProofRequest) Deadline() (time.Time, bool) {
+	return deadline(r.Ctx)
+}
+
+// Context implements utils.Contexter, so the time a request waits in the queue is traced.
+func (r ProofRequest) Context() context.Context {
+	return r.Ctx
+}
+
+type BatchProofResponse struct {
+	// Responses are in the order of the requested accounts.
+	Responses []ProofResponse
+}
+
+// BatchProofRequest asks for proofs of several accounts at once.
+// All proofs of a batch are generated against the same dictionary.
+type BatchProofRequest struct {
+	// Ctx is the context of the requester, the prover skips the request if it is done
+	// by the time a worker gets to the request. Nil means that the request never expires.
+	Ctx        context.Context
+	AccountIDs []ton.AccountID
+	ResponseCh chan<- BatchProofResponse
+}
+
+func (r BatchProofRequest) Deadline() (time.Time, bool) {
+	return deadline(r.Ctx)
+}
+
+func (r BatchProofRequest) Context() context.Context {
+	return r.Ctx
+}
+
 type EnumerateResponse struct {
 	WalletAirdrops []WalletAirdrop
 	NextFrom       ton.AccountID
@@ -31,21 +80,64 @@ type EnumerateResponse struct {
 }
 
 type EnumerateRequest struct {
+	// Ctx is the context of the requester, the prover skips the request if it is done
+	// by the time a worker gets to the request. Nil means that the request never expires.
+	Ctx        context.Context
 	NextFrom   ton.AccountID
 	Count      int
 	ResponseCh chan<- EnumerateResponse
 }
 
+func (r EnumerateRequest) Deadline() (time.Time, bool) {
+	return deadline(r.Ctx)
+}
+
+func (r EnumerateRequest) Context() context.Context {
+	return r.Ctx
+}
+
+func deadline(ctx context.Context) (time.Time, bool) {
+	if ctx == nil {
+		return time.Time{}, false
+	}
+	return ctx.Deadline()
+}
+
 type Prover struct {
-	logger       *zap.Logger
+	logger *zap.Logger
+	conf   Config
+	queue  *utils.ElasticQueue[any]
+	// running is set while Run is working.
+	running atomic.Bool
+
+	// reloadMu serializes reloads of the airdrop dictionary.
+	reloadMu sync.Mutex
+	dict     atomic.Pointer[dictionary]
+}
+
+// dictionary is an airdrop dictionary loaded from a file.
+// It is never changed after loading, a reload replaces it with a new one.
+type dictionary struct {
+	// root, merkleProver and entries are nil when only a proof store is configured.
 	root         *boc.Cell
-	queue        *utils.ElasticQueue[any]
 	merkleProver *boc.MerkleProver
 	merkleRoot   tlb.Bits256
+	// entries contains all accounts of the dictionary in the key order.
+	// It is an index for enumeration, so pages are found without walking the dictionary.
+	entries []walletData
+	// store contains precomputed proofs, if configured proofs are never generated on the fly.
+	store *proofStore
+	stats Stats
 }
 
 type Config struct {
 	Filename string
+	// ProofStoreFilename is a proof store built by BuildProofStore.
+	// If only a proof store is configured, enumeration of accounts isn't available.
+	ProofStoreFilename string
+	// Workers is a number of goroutines processing requests concurrently.
+	// Zero means the number of CPUs.
+	Workers int
 }
 
 type AirdropData struct {
@@ -60,8 +152,80 @@ type WalletAirdrop struct {
 	Proof     []byte
 }
 
+// Update describes how an airdrop dictionary has changed after a reload.
+type Update struct {
+	MerkleRoot tlb.Bits256
+	// Changed contains accounts that were added, removed or got different airdrop data.
+	// It is empty when only a proof store is configured, accounts can't be listed without a dictionary.
+	Changed []ton.AccountID
+}
+
 func NewProver(logger *zap.Logger, conf Config) (*Prover, error) {
-	content, err := os.ReadFile(conf.Filename)
+	dict, err := load(conf)
+	if err != nil {
+		return nil, err
+	}
+	p := &Prover{
+		logger: logger,
+		conf:   conf,
+		queue: utils.NewQueue[any]("prover", utils.WithLanes(
+			// proof lookups are cheap and users wait for them, so they go ahead of enumeration.
+			utils.Lane{Name: "proofs", Weight: 10, MaxLength: 1000, Overflow: utils.OverflowDropExpired},
+			utils.Lane{Name: "enumeration", Weight: 1, MaxLength: 100, Overflow: utils.OverflowDropExpired},
+		)),
+	}
+	p.dict.Store(dict)
+	return p, nil
+}
+
+// load loads an airdrop dictionary and a proof store according to the config.
+// Statistics are computed from the dictionary or, without it, read from the proof store,
+// so a proof store is never walked.
+func load(conf Config) (*dictionary, error) {
+	dict, err := loadFiles(conf)
+	if err != nil {
+		return nil, err
+	}
+	if dict.root == nil {
+		dict.stats = dict.store.stats
+	} else {
+		dict.stats = computeStats(dict.entries)
+	}
+	return dict, nil
+}
+
+func loadFiles(conf Config) (*dictionary, error) {
+	var dict *dictionary
+	if conf.Filename != "" {
+		var err error
+		dict, err = loadDictionary(conf.Filename)
+		if err != nil {
+			return nil, err
+		}
+	}
+	if conf.ProofStoreFilename == "" {
+		if dict == nil {
+			return nil, fmt.Errorf("neither airdrop dictionary nor proof store is configured")
+		}
+		return dict, nil
+	}
+	store, err := openProofStore(conf.ProofStoreFilename)
+	if err != nil {
+		return nil, err
+	}
+	if dict == nil {
+		return &dictionary{merkleRoot: store.merkleRoot, store: store}, nil
+	}
+	if dict.merkleRoot != store.merkleRoot {
+		return nil, fmt.Errorf("proof store is built for merkle root %v, airdrop dictionary has %v",
+			store.merkleRoot.Hex(), dict.merkleRoot.Hex())
+	}
+	dict.store = store
+	return dict, nil
+}
+
+func loadDictionary(filename string) (*dictionary, error) {
+	content, err := os.ReadFile(filename)
 	if err != nil {
 		return nil, err
 	}
@@ -76,30 +240,142 @@ func NewProver(logger *zap.Logger, conf Config) (*Prover, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to calculate merkle root: %w", err)
 	}
-	airdropCells[0].ResetCounters()
 	merkleProver, err := boc.NewMerkleProver(airdropCells[0])
 	if err != nil {
 		return nil, fmt.Errorf("failed to create merkle prover: %w", err)
 	}
-	return &Prover{
-		logger:       logger,
+	entries, err := enumerateAccounts(ton.AccountID{}, airdropCells[0], math.MaxInt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read airdrop data: %w", err)
+	}
+	return &dictionary{
 		root:         airdropCells[0],
 		merkleProver: merkleProver,
 		merkleRoot:   tlb.Bits256(merkleRoot),
-		queue:        utils.NewQueue[any]("prover", utils.WithMaxLength(1000)),
+		entries:      entries,
 	}, nil
 }
 
+// Reload reads the airdrop dictionary from the file again and swaps it in.
+// Requests being processed at the moment finish against the previous dictionary.
+// A new merkle root is passed to validate before the swap, the previous dictionary is kept if it fails.
+// Proofs generated against the previous dictionary aren't valid for the new one,
+// a caller regenerates the ones it keeps with BatchProofRequest once Reload returns.
+func (p *Prover) Reload(validate func(merkleRoot tlb.Bits256) error) (Update, error) {
+	timer := prometheus.NewTimer(prometheus.ObserverFunc(func(v float64) {
+		proverTimeHistogramVec.WithLabelValues("reload").Observe(v)
+	}))
+	defer timer.ObserveDuration()
+
+	p.reloadMu.Lock()
+	defer p.reloadMu.Unlock()
+
+	current := p.dict.Load()
+	dict, err := load(p.conf)
+	if err != nil {
+		return Update{}, err
+	}
+	update := Update{MerkleRoot: dict.merkleRoot}
+	if dict.merkleRoot == current.merkleRoot {
+		return update, nil
+	}
+	if err := validate(dict.merkleRoot); err != nil {
+		return Update{}, err
+	}
+	if dict.root != nil && current.root != nil {
+		update.Changed = changedAccounts(current.entries, dict.entries)
+	}
+	p.dict.Store(dict)
+	return update, nil
+}
+
+func (d *dictionary) prove(accountID ton.AccountID) (WalletAirdrop, error) {
+	if d.store != nil {
+		return d.store.lookup(accountID)
+	}
+	return prove(accountID, d.merkleProver, d.root)
+}
+
+func (d *dictionary) enumerate(nextFrom ton.AccountID, count int) ([]walletData, error) {
+	if d.root == nil {
+		return nil, fmt.Errorf("enumeration is not available without airdrop dictionary")
+	}
+	return seekEntries(d.entries, nextFrom, count), nil
+}
+
+func changedAccounts(previous, current []walletData) []ton.AccountID {
+	data := make(map[ton.AccountID]AirdropData, len(previous))
+	for _, entry := range previous {
+		data[entry.AccountID] = entry.Data
+	}
+	var changed []ton.AccountID
+	for _, entry := range current {
+		prev, ok := data[entry.AccountID]
+		delete(data, entry.AccountID)
+		if ok && prev == entry.Data {
+			continue
+		}
+		changed = append(changed, entry.AccountID)
+	}
+	for accountID := range data {
+		changed = append(changed, accountID)
+	}
+	return changed
+}
+
+// Queue returns the queue of proof requests.
 func (p *Prover) Queue() chan<- any {
 	return p.queue.Input()
 }
 
+// EnumerationQueue returns the queue of enumeration requests,
+// it gets a smaller share of workers than Queue while both have requests.
+func (p *Prover) EnumerationQueue() chan<- any {
+	return p.queue.LaneInput(1)
+}
+
+// Running reports whether the prover processes requests.
+func (p *Prover) Running() bool {
+	return p.running.Load()
+}
+
+// QueueLength returns the number of requests waiting in the queues.
+func (p *Prover) QueueLength() int {
+	return p.queue.Len()
+}
+
 func (p *Prover) MerkleRoot() tlb.Bits256 {
-	return p.merkleRoot
+	return p.dict.Load().merkleRoot
+}
+
+// Stats returns statistics of the current airdrop dictionary.
+func (p *Prover) Stats() Stats {
+	return p.dict.Load().stats
+}
+
+func (p *Prover) workers() int {
+	if p.conf.Workers <= 0 {
+		return runtime.NumCPU()
+	}
+	return p.conf.Workers
 }
 
 func (p *Prover) Run(ctx context.Context) {
+	p.running.Store(true)
+	defer p.running.Store(false)
 	go p.queue.Run(ctx)
+	var wg sync.WaitGroup
+	for i := 0; i < p.workers(); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.runWorker(ctx)
+		}()
+	}
+	wg.Wait()
+}
+
+func (p *Prover) runWorker(ctx context.Context) {
 	for {
 		select {
 		case <-ctx.Done():
@@ -107,9 +383,24 @@ func (p *Prover) Run(ctx context.Context) {
 		case reqAny := <-p.queue.Output():
 			switch req := reqAny.(type) {
 			case ProofRequest:
-				p.processProofRequest(req)
+				if !expired(req.Ctx, "ProofRequest") {
+					p.processProofRequest(req)
+				}
+			case BatchProofRequest:
+				if !expired(req.Ctx, "BatchProofRequest") {
+					p.processBatchProofRequest(req)
+				}
 			case EnumerateRequest:
-				p.processEnumerateAccountsRequest(req)
+				if !expired(req.Ctx, "EnumerateRequest") {
+					p.processEnumerateAccountsRequest(req)
+				}
+			case drainRequest:
+				req.received <- struct{}{}
+				select {
+				case <-ctx.Done():
+					return
+				case <-req.release:
+				}
 			default:
 				p.logger.Error("unexpected request type", zap.Any("reqAny", reqAny))
 			}
@@ -117,22 +408,100 @@ func (p *Prover) Run(ctx context.Context) {
 	}
 }
 
+// expired reports whether nobody waits for a request anymore.
+// An expired request is dropped without a response, its requester has already returned.
+func expired(ctx context.Context, request string) bool {
+	if ctx == nil || ctx.Err() == nil {
+		return false
+	}
+	proverExpiredRequestsCounterVec.WithLabelValues(request).Inc()
+	return true
+}
+
+// drainRequest is a barrier in the queue, see Drain.
+type drainRequest struct {
+	received chan<- struct{}
+	release  <-chan struct{}
+}
+
+// Drain waits until all requests sent to the queues before the call are processed.
+// It puts a barrier for every worker to the end of a queue and a worker that has reached a barrier
+// waits for the others, so once all barriers are reached, all requests before them are done.
+// Queues are drained one by one. The prover keeps running after Drain returns.
+func (p *Prover) Drain(ctx context.Context) error {
+	for i := 0; i < p.queue.Lanes(); i++ {
+		if err := p.drain(ctx, p.queue.LaneInput(i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Prover) drain(ctx context.Context, queue chan<- any) error {
+	workers := p.workers()
+	received := make(chan struct{}, workers)
+	release := make(chan struct{})
+	defer close(release)
+	req := drainRequest{received: received, release: release}
+	for i := 0; i < workers; i++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case queue <- req:
+		}
+	}
+	for i := 0; i < workers; i++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-received:
+		}
+	}
+	return nil
+}
+
 func (p *Prover) processProofRequest(req ProofRequest) {
 	timer := prometheus.NewTimer(prometheus.ObserverFunc(func(v float64) {
 		proverTimeHistogramVec.WithLabelValues("processProofRequest").Observe(v)
 	}))
 	defer timer.ObserveDuration()
+	span := startSpan(req.Ctx, "prover.ProofRequest", attribute.String("account", req.AccountID.ToRaw()))
 
-	walletAirdrop, err := prove(req.AccountID, p.merkleProver, p.root)
+	dict := p.dict.Load()
+	walletAirdrop, err := dict.prove(req.AccountID)
+	endSpan(span, err)
 	if err != nil {
 		req.ResponseCh <- ProofResponse{
-			Err: err,
+			MerkleRoot: dict.merkleRoot,
+			Err:        err,
 		}
 		return
 	}
 	req.ResponseCh <- ProofResponse{
 		WalletAirdrop: walletAirdrop,
+		MerkleRoot:    dict.merkleRoot,
+	}
+}
+
+func (p *Prover) processBatchProofRequest(req BatchProofRequest) {
+	timer := prometheus.NewTimer(prometheus.ObserverFunc(func(v float64) {
+		proverTimeHistogramVec.WithLabelValues("processBatchProofRequest").Observe(v)
+	}))
+	defer timer.ObserveDuration()
+	span := startSpan(req.Ctx, "prover.BatchProofRequest", attribute.Int("accounts", len(req.AccountIDs)))
+	defer span.End()
+
+	dict := p.dict.Load()
+	responses := make([]ProofResponse, 0, len(req.AccountIDs))
+	for _, accountID := range req.AccountIDs {
+		walletAirdrop, err := dict.prove(accountID)
+		responses = append(responses, ProofResponse{
+			WalletAirdrop: walletAirdrop,
+			MerkleRoot:    dict.merkleRoot,
+			Err:           err,
+		})
 	}
+	req.ResponseCh <- BatchProofResponse{Responses: responses}
 }
 
 func (p *Prover) processEnumerateAccountsRequest(req EnumerateRequest) {
@@ -140,8 +509,10 @@ func (p *Prover) processEnumerateAccountsRequest(req EnumerateRequest) {
 		proverTimeHistogramVec.WithLabelValues("processEnumerateAccountsRequest").Observe(v)
 	}))
 	defer timer.ObserveDuration()
+	span := startSpan(req.Ctx, "prover.EnumerateRequest", attribute.Int("count", req.Count))
 
-	walledDatas, err := enumerateAccounts(req.NextFrom, p.root, req.Count+1)
+	walledDatas, err := p.dict.Load().enumerate(req.NextFrom, req.Count+1)
+	endSpan(span, err)
 	if err != nil {
 		req.ResponseCh <- EnumerateResponse{
 			Err: err,
@@ -166,15 +537,56 @@ func (p *Prover) processEnumerateAccountsRequest(req EnumerateRequest) {
 	}
 }
 
+// prove looks up the given account in a dictionary and creates a merkle proof of its airdrop data.
+// It works like tlb.ProveKeyInHashmap but never touches read cursors of the dictionary cells,
+// so it is safe to call concurrently.
 func prove(accountID ton.AccountID, prover *boc.MerkleProver, root *boc.Cell) (WalletAirdrop, error) {
-	msgAddr := accountID.ToMsgAddress()
-	addrCell := boc.NewCell()
-	if err := tlb.Marshal(addrCell, msgAddr); err != nil {
+	key, err := accountIDToBitString(accountID)
+	if err != nil {
+		return WalletAirdrop{}, err
+	}
+	remaining := key.BitsAvailableForRead()
+	cursor := prover.Cursor()
+	cell := detach(root)
+	for {
+		size, label, err := readCommonPrefix(remaining, cell)
+		if err != nil {
+			return WalletAirdrop{}, err
+		}
+		keyPart, err := key.ReadBits(size)
+		if err != nil {
+			return WalletAirdrop{}, err
+		}
+		if keyPart.ToFiftHex() != label.ToFiftHex() {
+			return WalletAirdrop{}, errKeyNotFound
+		}
+		if remaining == size {
+			break
+		}
+		isRight, err := key.ReadBit()
+		if err != nil {
+			return WalletAirdrop{}, err
+		}
+		remaining = remaining - size - 1
+		refs := cell.Refs()
+		if len(refs) != 2 {
+			return WalletAirdrop{}, boc.ErrNotEnoughRefs
+		}
+		if isRight {
+			cursor.Ref(0).Prune()
+			cursor = cursor.Ref(1)
+			cell = detach(refs[1])
+		} else {
+			cursor.Ref(1).Prune()
+			cursor = cursor.Ref(0)
+			cell = detach(refs[0])
+		}
+	}
+	var data AirdropData
+	if err := tlb.Unmarshal(cell, &data); err != nil {
 		return WalletAirdrop{}, err
 	}
-	addrCell.ResetCounters()
-	root.ResetCounters()
-	data, proof, err := tlb.ProveKeyInHashmap[AirdropData](prover, root, addrCell.ReadRemainingBits())
+	proof, err := prover.CreateProof(cursor)
 	if err != nil {
 		return WalletAirdrop{}, err
 	}
@@ -186,13 +598,12 @@ func prove(accountID ton.AccountID, prover *boc.MerkleProver, root *boc.Cell) (W
 }
 
 func enumerateAccounts(nextFrom ton.AccountID, root *boc.Cell, count int) ([]walletData, error) {
-	root.ResetCounters()
 	prefix := boc.NewBitString(0)
 	startKey, err := accountIDToBitString(nextFrom)
 	if err != nil {
 		return nil, err
 	}
-	return walk(startKey, &prefix, root, count)
+	return walk(startKey, &prefix, detach(root), count)
 }
 
 func accountIDToBitString(accountID ton.AccountID) (*boc.BitString, error) {
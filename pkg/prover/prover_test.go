@@ -1,14 +1,21 @@
 package prover
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"path/filepath"
+	"sync"
 	"testing"
+	"time"
 
+	"github.com/prometheus/client_golang/prometheus/testutil"
 	"github.com/stretchr/testify/require"
 	"github.com/tonkeeper/tongo"
 	"github.com/tonkeeper/tongo/boc"
 	"github.com/tonkeeper/tongo/tlb"
 	"github.com/tonkeeper/tongo/ton"
+	"go.uber.org/zap"
 )
 
 type Address struct {
@@ -27,6 +34,10 @@ func (addr Address) FixedSize() int {
 	return 267
 }
 
+func (addr Address) MarshalTLB(c *boc.Cell, encoder *tlb.Encoder) error {
+	return encoder.Marshal(c, addr.MsgAddress)
+}
+
 func (addr *Address) UnmarshalTLB(c *boc.Cell, decoder *tlb.Decoder) error {
 	var msgAddr tlb.MsgAddress
 	if err := decoder.Unmarshal(c, &msgAddr); err != nil {
@@ -184,3 +195,262 @@ func Test_prove(t *testing.T) {
 		})
 	}
 }
+
+func writeAirdropDataFile(t testing.TB, filename string, hashmap tlb.Hashmap[Address, AirdropData]) {
+	cell := boc.NewCell()
+	require.Nil(t, tlb.Marshal(cell, hashmap))
+	content, err := cell.ToBoc()
+	require.Nil(t, err)
+	require.Nil(t, os.WriteFile(filename, content, 0644))
+}
+
+func TestProver_Reload(t *testing.T) {
+	_, hashmap := readAirdropDataFile(t, "testdata/airdropData.boc")
+	filename := filepath.Join(t.TempDir(), "airdropData.boc")
+	writeAirdropDataFile(t, filename, hashmap)
+
+	p, err := NewProver(zap.NewNop(), Config{Filename: filename})
+	require.Nil(t, err)
+	merkleRoot := p.MerkleRoot()
+
+	noValidation := func(tlb.Bits256) error { return nil }
+	update, err := p.Reload(noValidation)
+	require.Nil(t, err)
+	require.Equal(t, merkleRoot, update.MerkleRoot)
+	require.Empty(t, update.Changed)
+
+	keys := hashmap.Keys()
+	values := hashmap.Values()
+	modified := ton.MustParseAccountID(keys[0].ToRaw())
+	removed := ton.MustParseAccountID(keys[1].ToRaw())
+	unchanged := ton.MustParseAccountID(keys[2].ToRaw())
+	added := ton.MustParseAccountID("0:ff41b315c634b4ea4814b9262499567d36e9c7b13da09476f11a41d94e2cb700")
+
+	values[0].Amount += 1
+	newKeys := append([]Address{keys[0]}, keys[2:]...)
+	newValues := append([]AirdropData{values[0]}, values[2:]...)
+	newKeys = append(newKeys, Address{MsgAddress: added.ToMsgAddress()})
+	newValues = append(newValues, values[2])
+	writeAirdropDataFile(t, filename, tlb.NewHashmap(newKeys, newValues))
+
+	// a dictionary failing the validation is never served.
+	_, err = p.Reload(func(tlb.Bits256) error { return fmt.Errorf("merkle root mismatch") })
+	require.ErrorContains(t, err, "merkle root mismatch")
+	require.Equal(t, merkleRoot, p.MerkleRoot())
+
+	update, err = p.Reload(noValidation)
+	require.Nil(t, err)
+	require.NotEqual(t, merkleRoot, update.MerkleRoot)
+	require.Equal(t, update.MerkleRoot, p.MerkleRoot())
+	require.ElementsMatch(t, []ton.AccountID{modified, removed, added}, update.Changed)
+	walletAirdrop, err := p.dict.Load().prove(unchanged)
+	require.Nil(t, err)
+	require.Equal(t, values[2], walletAirdrop.Data)
+}
+
+func Test_proveConcurrently(t *testing.T) {
+	root, hashmap := readAirdropDataFile(t, "testdata/airdropData.boc")
+	merkleProver, err := boc.NewMerkleProver(root)
+	require.Nil(t, err)
+
+	expected := make(map[ton.AccountID][]byte)
+	for _, key := range hashmap.Keys() {
+		accountID := ton.MustParseAccountID(key.ToRaw())
+		bits, err := accountIDToBitString(accountID)
+		require.Nil(t, err)
+		root.ResetCounters()
+		_, proof, err := tlb.ProveKeyInHashmap[AirdropData](merkleProver, root, *bits)
+		require.Nil(t, err)
+		expected[accountID] = proof
+	}
+	all, err := enumerateAccounts(ton.AccountID{}, root, len(expected))
+	require.Nil(t, err)
+
+	// results are checked on the test goroutine, require must not be called from the others.
+	type proveResult struct {
+		accountID     ton.AccountID
+		walletAirdrop WalletAirdrop
+		err           error
+	}
+	type enumerateResult struct {
+		i     int
+		datas []walletData
+		err   error
+	}
+	proves := make(chan proveResult, 8*len(expected))
+	enumerations := make(chan enumerateResult, 8)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for accountID := range expected {
+				walletAirdrop, err := prove(accountID, merkleProver, root)
+				proves <- proveResult{accountID: accountID, walletAirdrop: walletAirdrop, err: err}
+			}
+		}()
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			datas, err := enumerateAccounts(all[i*10].AccountID, root, 20)
+			enumerations <- enumerateResult{i: i, datas: datas, err: err}
+		}(i)
+	}
+	wg.Wait()
+	close(proves)
+	close(enumerations)
+	for result := range proves {
+		require.Nil(t, result.err)
+		require.Equal(t, expected[result.accountID], result.walletAirdrop.Proof)
+	}
+	for result := range enumerations {
+		require.Nil(t, result.err)
+		require.Equal(t, all[result.i*10:result.i*10+20], result.datas)
+	}
+}
+
+func TestProver_RunConcurrently(t *testing.T) {
+	p, err := NewProver(zap.NewNop(), Config{Filename: "testdata/airdropData.boc", Workers: 4})
+	require.Nil(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.Run(ctx)
+
+	entries := p.dict.Load().entries
+	proofs := make(chan ProofResponse, len(entries))
+	type enumeration struct {
+		i    int
+		resp EnumerateResponse
+	}
+	enumerations := make(chan enumeration, 10)
+	var wg sync.WaitGroup
+	for _, entry := range entries {
+		wg.Add(1)
+		go func(entry walletData) {
+			defer wg.Done()
+			ch := make(chan ProofResponse, 1)
+			p.Queue() <- ProofRequest{AccountID: entry.AccountID, ResponseCh: ch}
+			proofs <- <-ch
+		}(entry)
+	}
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			ch := make(chan EnumerateResponse, 1)
+			p.EnumerationQueue() <- EnumerateRequest{NextFrom: entries[i].AccountID, Count: 5, ResponseCh: ch}
+			enumerations <- enumeration{i: i, resp: <-ch}
+		}(i)
+	}
+	wg.Wait()
+	close(proofs)
+	close(enumerations)
+
+	data := make(map[ton.AccountID]AirdropData, len(entries))
+	for _, entry := range entries {
+		data[entry.AccountID] = entry.Data
+	}
+	for resp := range proofs {
+		require.Nil(t, resp.Err)
+		require.Equal(t, data[resp.WalletAirdrop.AccountID], resp.WalletAirdrop.Data)
+		require.Equal(t, p.MerkleRoot(), resp.MerkleRoot)
+	}
+	for e := range enumerations {
+		require.Nil(t, e.resp.Err)
+		require.Equal(t, 5, len(e.resp.WalletAirdrops))
+		require.Equal(t, entries[e.i].AccountID, e.resp.WalletAirdrops[0].AccountID)
+		require.Equal(t, entries[e.i+5].AccountID, e.resp.NextFrom)
+	}
+}
+
+func TestProver_BatchProofRequest(t *testing.T) {
+	p, err := NewProver(zap.NewNop(), Config{Filename: "testdata/airdropData.boc", Workers: 2})
+	require.Nil(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.Run(ctx)
+
+	entries := p.dict.Load().entries
+	missing := ton.MustParseAccountID("0:ff41b315c634b4ea4814b9262499567d36e9c7b13da09476f11a41d94e2cb700")
+	ch := make(chan BatchProofResponse, 1)
+	p.Queue() <- BatchProofRequest{
+		AccountIDs: []ton.AccountID{entries[3].AccountID, missing, entries[0].AccountID},
+		ResponseCh: ch,
+	}
+	resp := <-ch
+	require.Equal(t, 3, len(resp.Responses))
+	require.Nil(t, resp.Responses[0].Err)
+	require.Equal(t, entries[3].Data, resp.Responses[0].WalletAirdrop.Data)
+	require.ErrorIs(t, resp.Responses[1].Err, errKeyNotFound)
+	require.Nil(t, resp.Responses[2].Err)
+	require.Equal(t, entries[0].AccountID, resp.Responses[2].WalletAirdrop.AccountID)
+	for _, r := range resp.Responses {
+		require.Equal(t, p.MerkleRoot(), r.MerkleRoot)
+	}
+}
+
+func TestProver_Drain(t *testing.T) {
+	p, err := NewProver(zap.NewNop(), Config{Filename: "testdata/airdropData.boc", Workers: 3})
+	require.Nil(t, err)
+
+	// the prover isn't running, so nothing can be drained.
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	require.ErrorIs(t, p.Drain(ctx), context.DeadlineExceeded)
+
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	go p.Run(ctx)
+	entries := p.dict.Load().entries
+	var channels []chan ProofResponse
+	for _, entry := range entries {
+		ch := make(chan ProofResponse, 1)
+		p.Queue() <- ProofRequest{AccountID: entry.AccountID, ResponseCh: ch}
+		channels = append(channels, ch)
+	}
+	require.Nil(t, p.Drain(context.Background()))
+	for _, ch := range channels {
+		require.Equal(t, 1, len(ch))
+	}
+
+	// the prover keeps working after draining.
+	ch := make(chan ProofResponse, 1)
+	p.Queue() <- ProofRequest{AccountID: entries[0].AccountID, ResponseCh: ch}
+	require.Nil(t, (<-ch).Err)
+}
+
+func TestProver_expiredRequests(t *testing.T) {
+	p, err := NewProver(zap.NewNop(), Config{Filename: "testdata/airdropData.boc", Workers: 2})
+	require.Nil(t, err)
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go p.Run(ctx)
+
+	expiredCtx, expire := context.WithCancel(context.Background())
+	expire()
+	before := testutil.ToFloat64(proverExpiredRequestsCounterVec.WithLabelValues("ProofRequest")) +
+		testutil.ToFloat64(proverExpiredRequestsCounterVec.WithLabelValues("BatchProofRequest")) +
+		testutil.ToFloat64(proverExpiredRequestsCounterVec.WithLabelValues("EnumerateRequest"))
+
+	accountID := p.dict.Load().entries[0].AccountID
+	proofCh := make(chan ProofResponse, 1)
+	p.Queue() <- ProofRequest{Ctx: expiredCtx, AccountID: accountID, ResponseCh: proofCh}
+	batchCh := make(chan BatchProofResponse, 1)
+	p.Queue() <- BatchProofRequest{Ctx: expiredCtx, AccountIDs: []ton.AccountID{accountID}, ResponseCh: batchCh}
+	enumerateCh := make(chan EnumerateResponse, 1)
+	p.EnumerationQueue() <- EnumerateRequest{Ctx: expiredCtx, Count: 10, ResponseCh: enumerateCh}
+	require.Nil(t, p.Drain(context.Background()))
+
+	// nobody waits for expired requests, so they get no responses.
+	require.Equal(t, 0, len(proofCh))
+	require.Equal(t, 0, len(batchCh))
+	require.Equal(t, 0, len(enumerateCh))
+	after := testutil.ToFloat64(proverExpiredRequestsCounterVec.WithLabelValues("ProofRequest")) +
+		testutil.ToFloat64(proverExpiredRequestsCounterVec.WithLabelValues("BatchProofRequest")) +
+		testutil.ToFloat64(proverExpiredRequestsCounterVec.WithLabelValues("EnumerateRequest"))
+	require.Equal(t, before+3, after)
+
+	// a request with a live context is processed as usual.
+	p.Queue() <- ProofRequest{Ctx: ctx, AccountID: accountID, ResponseCh: proofCh}
+	require.Nil(t, (<-proofCh).Err)
+}
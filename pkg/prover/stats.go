@@ -0,0 +1,77 @@
+package prover
+
+import (
+	"math/big"
+	"slices"
+	"time"
+
+	"github.com/tonkeeper/tongo/tlb"
+)
+
+// statsBucketSize is a width of a histogram bucket of StartFrom and ExpireAt.
+const statsBucketSize = int64(24 * time.Hour / time.Second)
+
+// Stats summarizes airdrop data of a dictionary.
+// It is computed once when a dictionary is loaded and must not be modified.
+type Stats struct {
+	Recipients  int
+	TotalAmount *big.Int
+	MinAmount   tlb.Coins
+	MaxAmount   tlb.Coins
+	// MedianAmount is the lower median for an even number of recipients.
+	MedianAmount tlb.Coins
+	// StartFrom and ExpireAt are histograms with daily buckets in ascending order.
+	// Empty buckets are omitted.
+	StartFrom []HistogramBucket
+	ExpireAt  []HistogramBucket
+}
+
+// HistogramBucket counts recipients whose timestamp is in [From, From+24h).
+type HistogramBucket struct {
+	// From is a unix time of the beginning of a UTC day.
+	From  int64
+	Count int
+}
+
+func computeStats(entries []walletData) Stats {
+	stats := Stats{
+		Recipients:  len(entries),
+		TotalAmount: new(big.Int),
+	}
+	if len(entries) == 0 {
+		return stats
+	}
+	amounts := make([]tlb.Coins, 0, len(entries))
+	startFrom := make(map[int64]int)
+	expireAt := make(map[int64]int)
+	var amount big.Int
+	for _, entry := range entries {
+		amounts = append(amounts, entry.Data.Amount)
+		stats.TotalAmount.Add(stats.TotalAmount, amount.SetUint64(uint64(entry.Data.Amount)))
+		startFrom[statsBucket(entry.Data.StartFrom)]++
+		expireAt[statsBucket(entry.Data.ExpireAt)]++
+	}
+	slices.Sort(amounts)
+	stats.MinAmount = amounts[0]
+	stats.MaxAmount = amounts[len(amounts)-1]
+	stats.MedianAmount = amounts[(len(amounts)-1)/2]
+	stats.StartFrom = histogram(startFrom)
+	stats.ExpireAt = histogram(expireAt)
+	return stats
+}
+
+func statsBucket(timestamp tlb.Uint48) int64 {
+	t := int64(timestamp)
+	return t - t%statsBucketSize
+}
+
+func histogram(buckets map[int64]int) []HistogramBucket {
+	result := make([]HistogramBucket, 0, len(buckets))
+	for from, count := range buckets {
+		result = append(result, HistogramBucket{From: from, Count: count})
+	}
+	slices.SortFunc(result, func(a, b HistogramBucket) int {
+		return int(a.From - b.From)
+	})
+	return result
+}
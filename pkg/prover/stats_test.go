@@ -0,0 +1,32 @@
+package prover
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tonkeeper/tongo/tlb"
+)
+
+func Test_computeStats(t *testing.T) {
+	entries := []walletData{
+		{Data: AirdropData{Amount: 30, StartFrom: 1727000000, ExpireAt: 1730000000}},
+		{Data: AirdropData{Amount: 10, StartFrom: 1727000100, ExpireAt: 1730000000}},
+		{Data: AirdropData{Amount: 20, StartFrom: 1726000000, ExpireAt: 1730000000}},
+		{Data: AirdropData{Amount: 18446744073709551615, StartFrom: 1727000000, ExpireAt: 1730000000}},
+	}
+	stats := computeStats(entries)
+	require.Equal(t, 4, stats.Recipients)
+	require.Equal(t, "18446744073709551675", stats.TotalAmount.String())
+	require.Equal(t, tlb.Coins(10), stats.MinAmount)
+	require.Equal(t, tlb.Coins(18446744073709551615), stats.MaxAmount)
+	require.Equal(t, tlb.Coins(20), stats.MedianAmount)
+	require.Equal(t, []HistogramBucket{
+		{From: 1725926400, Count: 1},
+		{From: 1726963200, Count: 3},
+	}, stats.StartFrom)
+	require.Equal(t, []HistogramBucket{{From: 1729987200, Count: 4}}, stats.ExpireAt)
+
+	empty := computeStats(nil)
+	require.Equal(t, 0, empty.Recipients)
+	require.Equal(t, "0", empty.TotalAmount.String())
+}
@@ -0,0 +1,316 @@
+package prover
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sync"
+
+	"github.com/tonkeeper/tongo/tlb"
+	"github.com/tonkeeper/tongo/ton"
+)
+
+// A proof store is a file with precomputed proofs of all accounts of an airdrop dictionary.
+// It lets a prover serve proofs without keeping the dictionary in memory.
+//
+// The file consists of four sections, all integers are little-endian:
+//
+//	header:  magic[8] | merkle root[32] | number of records: uint64 | number of slots: uint64 | stats offset: uint64
+//	slots:   open addressing hash table, every slot is workchain: int32 | address[32] | record offset: uint64
+//	records: amount: uint64 | start from: uint64 | expire at: uint64 | proof length: uint32 | proof
+//	stats:   recipients: uint64 | total amount length: uint32 | total amount: big-endian bytes |
+//	         min, max and median amounts: uint64 | start from and expire at histograms
+//
+// A histogram is a number of buckets: uint32 followed by buckets, every bucket is from: int64 | count: uint64.
+// Stats are computed when the store is built, so a prover serving a store never walks its records.
+//
+// A slot is chosen by the first 8 bytes of an address, which are uniformly distributed,
+// and collisions are resolved by linear probing. An empty slot has zero record offset.
+// Records follow the key order of the dictionary.
+
+var proofStoreMagic = [8]byte{'c', 'l', 'a', 'i', 'm', 'p', 's', 2}
+
+const (
+	proofStoreHeaderSize = 8 + 32 + 8 + 8 + 8
+	proofStoreSlotSize   = 4 + 32 + 8
+	proofStoreRecordSize = 8 + 8 + 8 + 4
+)
+
+type proofStore struct {
+	data       []byte
+	merkleRoot tlb.Bits256
+	count      uint64
+	slots      uint64
+	stats      Stats
+}
+
+// BuildProofStore generates proofs for every account of the given airdrop dictionary
+// and writes them to a proof store file.
+func BuildProofStore(airdropFilename, storeFilename string, workers int) error {
+	dict, err := loadDictionary(airdropFilename)
+	if err != nil {
+		return err
+	}
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	proofs := make([][]byte, len(dict.entries))
+	errs := make([]error, workers)
+	var wg sync.WaitGroup
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := w; i < len(dict.entries); i += workers {
+				walletAirdrop, err := prove(dict.entries[i].AccountID, dict.merkleProver, dict.root)
+				if err != nil {
+					errs[w] = fmt.Errorf("failed to prove %v: %w", dict.entries[i].AccountID.ToRaw(), err)
+					return
+				}
+				proofs[i] = walletAirdrop.Proof
+			}
+		}(w)
+	}
+	wg.Wait()
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return writeProofStore(storeFilename, dict.merkleRoot, dict.entries, proofs)
+}
+
+func writeProofStore(filename string, merkleRoot tlb.Bits256, entries []walletData, proofs [][]byte) error {
+	stats := encodeStats(computeStats(entries))
+	slots := uint64(1)
+	// keep the table at most half full, so that probe sequences stay short.
+	for slots < 2*uint64(len(entries)) {
+		slots <<= 1
+	}
+	table := make([]byte, slots*proofStoreSlotSize)
+	offset := uint64(proofStoreHeaderSize) + uint64(len(table))
+	for i, entry := range entries {
+		slot := proofStoreSlot(entry.AccountID, slots)
+		for binary.LittleEndian.Uint64(table[slot*proofStoreSlotSize+36:]) != 0 {
+			slot = (slot + 1) & (slots - 1)
+		}
+		s := table[slot*proofStoreSlotSize:]
+		binary.LittleEndian.PutUint32(s, uint32(entry.AccountID.Workchain))
+		copy(s[4:36], entry.AccountID.Address[:])
+		binary.LittleEndian.PutUint64(s[36:], offset)
+		offset += proofStoreRecordSize + uint64(len(proofs[i]))
+	}
+	statsOffset := offset
+
+	tmp, err := os.CreateTemp(filepath.Dir(filename), filepath.Base(filename)+".*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	defer tmp.Close()
+
+	w := bufio.NewWriter(tmp)
+	header := make([]byte, proofStoreHeaderSize)
+	copy(header, proofStoreMagic[:])
+	copy(header[8:40], merkleRoot[:])
+	binary.LittleEndian.PutUint64(header[40:], uint64(len(entries)))
+	binary.LittleEndian.PutUint64(header[48:], slots)
+	binary.LittleEndian.PutUint64(header[56:], statsOffset)
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	if _, err := w.Write(table); err != nil {
+		return err
+	}
+	record := make([]byte, proofStoreRecordSize)
+	for i, entry := range entries {
+		binary.LittleEndian.PutUint64(record, uint64(entry.Data.Amount))
+		binary.LittleEndian.PutUint64(record[8:], uint64(entry.Data.StartFrom))
+		binary.LittleEndian.PutUint64(record[16:], uint64(entry.Data.ExpireAt))
+		binary.LittleEndian.PutUint32(record[24:], uint32(len(proofs[i])))
+		if _, err := w.Write(record); err != nil {
+			return err
+		}
+		if _, err := w.Write(proofs[i]); err != nil {
+			return err
+		}
+	}
+	if _, err := w.Write(stats); err != nil {
+		return err
+	}
+	if err := w.Flush(); err != nil {
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp.Name(), filename)
+}
+
+func proofStoreSlot(accountID ton.AccountID, slots uint64) uint64 {
+	return binary.LittleEndian.Uint64(accountID.Address[:8]) & (slots - 1)
+}
+
+// openProofStore maps a proof store file into memory.
+// The mapping is released once the store becomes unreachable.
+func openProofStore(filename string) (*proofStore, error) {
+	data, err := mmapFile(filename)
+	if err != nil {
+		return nil, err
+	}
+	store, err := newProofStore(data)
+	if err != nil {
+		munmapFile(data)
+		return nil, fmt.Errorf("invalid proof store %v: %w", filename, err)
+	}
+	runtime.SetFinalizer(store, func(s *proofStore) {
+		munmapFile(s.data)
+	})
+	return store, nil
+}
+
+func newProofStore(data []byte) (*proofStore, error) {
+	if len(data) < proofStoreHeaderSize || !bytes.Equal(data[:8], proofStoreMagic[:]) {
+		return nil, fmt.Errorf("unknown file format")
+	}
+	store := &proofStore{
+		data:  data,
+		count: binary.LittleEndian.Uint64(data[40:]),
+		slots: binary.LittleEndian.Uint64(data[48:]),
+	}
+	copy(store.merkleRoot[:], data[8:40])
+	if store.slots == 0 || store.slots&(store.slots-1) != 0 || store.slots < store.count {
+		return nil, fmt.Errorf("invalid number of slots: %v", store.slots)
+	}
+	if uint64(len(data)) < proofStoreHeaderSize+store.slots*proofStoreSlotSize {
+		return nil, fmt.Errorf("file is truncated")
+	}
+	statsOffset := binary.LittleEndian.Uint64(data[56:])
+	if statsOffset < proofStoreHeaderSize+store.slots*proofStoreSlotSize || statsOffset > uint64(len(data)) {
+		return nil, fmt.Errorf("invalid stats offset: %v", statsOffset)
+	}
+	stats, err := decodeStats(data[statsOffset:])
+	if err != nil {
+		return nil, fmt.Errorf("invalid stats: %w", err)
+	}
+	store.stats = stats
+	return store, nil
+}
+
+// lookup returns airdrop data and a proof of the given account in O(1).
+func (s *proofStore) lookup(accountID ton.AccountID) (WalletAirdrop, error) {
+	slot := proofStoreSlot(accountID, s.slots)
+	for i := uint64(0); i < s.slots; i++ {
+		entry := s.data[proofStoreHeaderSize+slot*proofStoreSlotSize:]
+		offset := binary.LittleEndian.Uint64(entry[36:])
+		if offset == 0 {
+			break
+		}
+		if int32(binary.LittleEndian.Uint32(entry)) == accountID.Workchain && bytes.Equal(entry[4:36], accountID.Address[:]) {
+			data, proof, err := s.record(offset)
+			if err != nil {
+				return WalletAirdrop{}, err
+			}
+			// the mapping is released when the store is collected, so the proof must be copied.
+			proof = bytes.Clone(proof)
+			runtime.KeepAlive(s)
+			return WalletAirdrop{
+				AccountID: accountID,
+				Data:      data,
+				Proof:     proof,
+			}, nil
+		}
+		slot = (slot + 1) & (s.slots - 1)
+	}
+	return WalletAirdrop{}, errKeyNotFound
+}
+
+func (s *proofStore) record(offset uint64) (AirdropData, []byte, error) {
+	if offset+proofStoreRecordSize > uint64(len(s.data)) {
+		return AirdropData{}, nil, fmt.Errorf("record at %v is out of the file", offset)
+	}
+	record := s.data[offset:]
+	data := AirdropData{
+		Amount:    tlb.Coins(binary.LittleEndian.Uint64(record)),
+		StartFrom: tlb.Uint48(binary.LittleEndian.Uint64(record[8:])),
+		ExpireAt:  tlb.Uint48(binary.LittleEndian.Uint64(record[16:])),
+	}
+	proofLen := uint64(binary.LittleEndian.Uint32(record[24:]))
+	end := offset + proofStoreRecordSize + proofLen
+	if end > uint64(len(s.data)) {
+		return AirdropData{}, nil, fmt.Errorf("record at %v is out of the file", offset)
+	}
+	return data, s.data[offset+proofStoreRecordSize : end], nil
+}
+
+func encodeStats(stats Stats) []byte {
+	total := stats.TotalAmount.Bytes()
+	buf := binary.LittleEndian.AppendUint64(nil, uint64(stats.Recipients))
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(total)))
+	buf = append(buf, total...)
+	buf = binary.LittleEndian.AppendUint64(buf, uint64(stats.MinAmount))
+	buf = binary.LittleEndian.AppendUint64(buf, uint64(stats.MaxAmount))
+	buf = binary.LittleEndian.AppendUint64(buf, uint64(stats.MedianAmount))
+	for _, histogram := range [][]HistogramBucket{stats.StartFrom, stats.ExpireAt} {
+		buf = binary.LittleEndian.AppendUint32(buf, uint32(len(histogram)))
+		for _, bucket := range histogram {
+			buf = binary.LittleEndian.AppendUint64(buf, uint64(bucket.From))
+			buf = binary.LittleEndian.AppendUint64(buf, uint64(bucket.Count))
+		}
+	}
+	return buf
+}
+
+// statsReader reads little-endian integers and remembers the first overrun of the data.
+type statsReader struct {
+	data []byte
+	err  error
+}
+
+func (r *statsReader) next(n int) []byte {
+	if r.err != nil || len(r.data) < n {
+		r.err = fmt.Errorf("stats are truncated")
+		return make([]byte, n)
+	}
+	b := r.data[:n]
+	r.data = r.data[n:]
+	return b
+}
+
+func (r *statsReader) uint32() uint32 {
+	return binary.LittleEndian.Uint32(r.next(4))
+}
+
+func (r *statsReader) uint64() uint64 {
+	return binary.LittleEndian.Uint64(r.next(8))
+}
+
+func decodeStats(data []byte) (Stats, error) {
+	r := &statsReader{data: data}
+	stats := Stats{Recipients: int(r.uint64())}
+	total := r.next(int(r.uint32()))
+	stats.TotalAmount = new(big.Int).SetBytes(total)
+	stats.MinAmount = tlb.Coins(r.uint64())
+	stats.MaxAmount = tlb.Coins(r.uint64())
+	stats.MedianAmount = tlb.Coins(r.uint64())
+	histograms := make([][]HistogramBucket, 2)
+	for i := range histograms {
+		n := r.uint32()
+		// every bucket takes 16 bytes, a corrupted length mustn't allocate more than the file has.
+		if uint64(n)*16 > uint64(len(r.data)) {
+			return Stats{}, fmt.Errorf("stats are truncated")
+		}
+		histogram := make([]HistogramBucket, 0, n)
+		for j := uint32(0); j < n; j++ {
+			histogram = append(histogram, HistogramBucket{From: int64(r.uint64()), Count: int(r.uint64())})
+		}
+		histograms[i] = histogram
+	}
+	stats.StartFrom, stats.ExpireAt = histograms[0], histograms[1]
+	return stats, r.err
+}
@@ -0,0 +1,90 @@
+package prover
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tonkeeper/tongo/tlb"
+	"github.com/tonkeeper/tongo/ton"
+	"go.uber.org/zap"
+)
+
+func TestBuildProofStore(t *testing.T) {
+	storeFilename := filepath.Join(t.TempDir(), "proofs.store")
+	require.Nil(t, BuildProofStore("testdata/airdropData.boc", storeFilename, 4))
+
+	dict, err := loadDictionary("testdata/airdropData.boc")
+	require.Nil(t, err)
+	store, err := openProofStore(storeFilename)
+	require.Nil(t, err)
+	require.Equal(t, dict.merkleRoot, store.merkleRoot)
+	require.Equal(t, uint64(len(dict.entries)), store.count)
+
+	for _, entry := range dict.entries {
+		expected, err := prove(entry.AccountID, dict.merkleProver, dict.root)
+		require.Nil(t, err)
+		walletAirdrop, err := store.lookup(entry.AccountID)
+		require.Nil(t, err)
+		require.Equal(t, expected, walletAirdrop)
+	}
+	_, err = store.lookup(ton.MustParseAccountID("0:ff41b315c634b4ea4814b9262499567d36e9c7b13da09476f11a41d94e2cb700"))
+	require.ErrorIs(t, err, errKeyNotFound)
+
+	// stats are read from the store instead of walking its records.
+	require.Equal(t, computeStats(dict.entries), store.stats)
+}
+
+func TestNewProver_proofStore(t *testing.T) {
+	storeFilename := filepath.Join(t.TempDir(), "proofs.store")
+	require.Nil(t, BuildProofStore("testdata/airdropData.boc", storeFilename, 0))
+
+	p, err := NewProver(zap.NewNop(), Config{ProofStoreFilename: storeFilename})
+	require.Nil(t, err)
+	dict, err := loadDictionary("testdata/airdropData.boc")
+	require.Nil(t, err)
+	require.Equal(t, dict.merkleRoot, p.MerkleRoot())
+	require.Equal(t, computeStats(dict.entries), p.Stats())
+
+	walletAirdrop, err := p.dict.Load().prove(dict.entries[10].AccountID)
+	require.Nil(t, err)
+	require.Equal(t, dict.entries[10].Data, walletAirdrop.Data)
+	_, err = p.dict.Load().enumerate(ton.AccountID{}, 10)
+	require.NotNil(t, err)
+
+	_, hashmap := readAirdropDataFile(t, "testdata/airdropData.boc")
+	airdropFilename := filepath.Join(t.TempDir(), "airdropData.boc")
+	values := hashmap.Values()
+	values[0].Amount += 1
+	writeAirdropDataFile(t, airdropFilename, hashmap)
+	_, err = NewProver(zap.NewNop(), Config{Filename: airdropFilename, ProofStoreFilename: storeFilename})
+	require.ErrorContains(t, err, "proof store is built for merkle root")
+}
+
+func TestProver_ReloadProofStore(t *testing.T) {
+	storeFilename := filepath.Join(t.TempDir(), "proofs.store")
+	require.Nil(t, BuildProofStore("testdata/airdropData.boc", storeFilename, 0))
+	p, err := NewProver(zap.NewNop(), Config{ProofStoreFilename: storeFilename})
+	require.Nil(t, err)
+
+	_, hashmap := readAirdropDataFile(t, "testdata/airdropData.boc")
+	keys := hashmap.Keys()
+	values := hashmap.Values()
+	modified := ton.MustParseAccountID(keys[0].ToRaw())
+	removed := ton.MustParseAccountID(keys[1].ToRaw())
+	values[0].Amount += 1
+	airdropFilename := filepath.Join(t.TempDir(), "airdropData.boc")
+	writeAirdropDataFile(t, airdropFilename, tlb.NewHashmap(append([]Address{keys[0]}, keys[2:]...), append([]AirdropData{values[0]}, values[2:]...)))
+	require.Nil(t, BuildProofStore(airdropFilename, storeFilename, 0))
+
+	// without a dictionary accounts aren't compared, the new store is served as is.
+	update, err := p.Reload(func(tlb.Bits256) error { return nil })
+	require.Nil(t, err)
+	require.Empty(t, update.Changed)
+	walletAirdrop, err := p.dict.Load().prove(modified)
+	require.Nil(t, err)
+	require.Equal(t, values[0], walletAirdrop.Data)
+	_, err = p.dict.Load().prove(removed)
+	require.ErrorIs(t, err, errKeyNotFound)
+	require.Equal(t, len(values)-1, p.Stats().Recipients)
+}
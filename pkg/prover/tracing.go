@@ -0,0 +1,29 @@
+package prover
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+var tracer = otel.Tracer("github.com/tonkeeper/claim-api-go/pkg/prover")
+
+// startSpan starts a span of processing a request, a request without a context starts a new trace.
+func startSpan(ctx context.Context, name string, attributes ...attribute.KeyValue) trace.Span {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	_, span := tracer.Start(ctx, name, trace.WithAttributes(attributes...))
+	return span
+}
+
+func endSpan(span trace.Span, err error) {
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
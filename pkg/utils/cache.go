@@ -1,6 +1,8 @@
 package utils
 
 import (
+	"sync/atomic"
+
 	cache "github.com/Code-Hex/go-generics-cache"
 	"github.com/Code-Hex/go-generics-cache/policy/lru"
 	"github.com/prometheus/client_golang/prometheus"
@@ -18,6 +20,17 @@ type Cache[K comparable, V any] struct {
 	cache      *cache.Cache[K, V]
 	metricName string
 	size       int
+	// hits and misses are shared by copies of the cache.
+	hits   *atomic.Uint64
+	misses *atomic.Uint64
+}
+
+// CacheStats describes the content and efficiency of a cache since it was created.
+type CacheStats struct {
+	Len      int    `json:"len"`
+	Capacity int    `json:"capacity"`
+	Hits     uint64 `json:"hits"`
+	Misses   uint64 `json:"misses"`
 }
 
 func NewLRUCache[K comparable, V any](size int, metricName string) Cache[K, V] {
@@ -25,15 +38,19 @@ func NewLRUCache[K comparable, V any](size int, metricName string) Cache[K, V] {
 		cache:      cache.New(cache.AsLRU[K, V](lru.WithCapacity(size))),
 		metricName: metricName,
 		size:       size,
+		hits:       &atomic.Uint64{},
+		misses:     &atomic.Uint64{},
 	}
 }
 
 func (c *Cache[K, V]) Get(key K) (V, bool) {
 	val, ok := c.cache.Get(key)
 	if ok {
+		c.hits.Add(1)
 		cacheMetrics.WithLabelValues(c.metricName, "hit").Inc()
 		return val, ok
 	}
+	c.misses.Add(1)
 	cacheMetrics.WithLabelValues(c.metricName, "miss").Inc()
 	return val, ok
 }
@@ -50,4 +67,20 @@ func (c *Cache[K, V]) Keys() []K {
 	return c.cache.Keys()
 }
 
+// Purge deletes all keys of the cache.
+func (c *Cache[K, V]) Purge() {
+	for _, key := range c.cache.Keys() {
+		c.cache.Delete(key)
+	}
+}
+
+func (c *Cache[K, V]) Stats() CacheStats {
+	return CacheStats{
+		Len:      c.cache.Len(),
+		Capacity: c.size,
+		Hits:     c.hits.Load(),
+		Misses:   c.misses.Load(),
+	}
+}
+
 var WithExpiration = cache.WithExpiration
@@ -2,17 +2,25 @@ package utils
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"github.com/prometheus/client_golang/prometheus"
 	"github.com/prometheus/client_golang/prometheus/promauto"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
+var tracer = otel.Tracer("github.com/tonkeeper/claim-api-go/pkg/utils")
+
+var queueTimeBuckets = []float64{0.1, 0.5, 1, 2, 3, 4, 5, 7.5, 10, 20, 30, 60, 120, 180, 240, 300, 500, 1000}
+
 var queueTimeHistogramVec = promauto.NewHistogramVec(
 	prometheus.HistogramOpts{
 		Name:    "queue_waiting_time",
 		Help:    "elastic queue waiting time distribution in seconds",
-		Buckets: []float64{0.1, 0.5, 1, 2, 3, 4, 5, 7.5, 10, 20, 30, 60, 120, 180, 240, 300, 500, 1000},
+		Buckets: queueTimeBuckets,
 	},
 	[]string{"name"},
 )
@@ -25,16 +33,114 @@ var queueLength = promauto.NewGaugeVec(
 	[]string{"name"},
 )
 
+// queueLaneTimeHistogramVec and queueLaneLength split queueTimeHistogramVec and queueLength by lanes.
+var queueLaneTimeHistogramVec = promauto.NewHistogramVec(
+	prometheus.HistogramOpts{
+		Name:    "queue_lane_waiting_time",
+		Help:    "elastic queue waiting time distribution in seconds by lanes",
+		Buckets: queueTimeBuckets,
+	},
+	[]string{"name", "lane"},
+)
+
+var queueLaneLength = promauto.NewGaugeVec(
+	prometheus.GaugeOpts{
+		Name: "queue_lane_length",
+		Help: "elastic queue length by lanes",
+	},
+	[]string{"name", "lane"},
+)
+
+var queueDropped = promauto.NewCounterVec(
+	prometheus.CounterOpts{
+		Name: "queue_dropped_total",
+		Help: "number of messages dropped by an overflow policy of an elastic queue",
+	},
+	[]string{"name", "lane", "reason"},
+)
+
+// DefaultLane is a name of the only lane of a queue created without WithLanes.
+const DefaultLane = "default"
+
+// strideScale is divided by a lane weight to get the stride of the lane.
+const strideScale = 1 << 20
+
+// Overflow is a policy of a lane that has reached its max length.
+type Overflow int
+
+const (
+	// OverflowBlock stops reading the input of a full lane, so producers block until there is space.
+	OverflowBlock Overflow = iota
+	// OverflowRejectNewest drops messages arriving to a full lane.
+	OverflowRejectNewest
+	// OverflowDropOldest drops the oldest message of a full lane to make space for a new one.
+	OverflowDropOldest
+	// OverflowDropExpired drops messages past their deadline, see Deadliner.
+	// Expired messages are dropped when a lane is full and instead of being dequeued.
+	// If a full lane has no expired messages, producers block as with OverflowBlock.
+	OverflowDropExpired
+)
+
+// Deadliner is implemented by messages that are useless after a deadline, see OverflowDropExpired.
+type Deadliner interface {
+	Deadline() (deadline time.Time, ok bool)
+}
+
+// Contexter is implemented by messages carrying a context of their producer,
+// a queue records a span of the time such a message has waited in the queue.
+type Contexter interface {
+	Context() context.Context
+}
+
+// ElasticQueue buffers messages between producers and consumers.
+// Messages are split into lanes, every lane is FIFO and lanes share the output in proportion to their weights,
+// so a burst of messages in one lane doesn't starve the others.
 type ElasticQueue[T any] struct {
-	input     chan T
-	output    chan T
+	output chan T
+	name   string
+	lanes  []*lane[T]
+
+	mu sync.Mutex
+	// pass is the pass of the last dequeued lane, see lane.pass.
+	pass uint64
+}
+
+type lane[T any] struct {
 	name      string
+	input     chan T
 	maxLength int
+	overflow  Overflow
+	// stride is inversely proportional to the weight of the lane.
+	stride uint64
+	// pass grows by stride with every dequeued message, a non-empty lane with the lowest pass goes next.
+	pass uint64
+	msgs ring[message[T]]
+	// sending is set while the head of the lane is being sent to the output, it is counted in the length.
+	sending bool
+	// space is signaled when a message leaves the lane, so a full lane accepts input again.
+	space chan struct{}
+}
+
+// Lane is a priority class of messages of a queue.
+type Lane struct {
+	Name string
+	// Weight is a relative share of the output the lane gets while other lanes are not empty.
+	// Zero means 1.
+	Weight int
+	// MaxLength limits the number of queued messages of the lane, zero means unlimited.
+	MaxLength int
+	// Overflow defines what happens to messages once the lane is full.
+	Overflow Overflow
 }
 
 type Options struct {
 	MaxLength         int
 	InputQueueChanLen int
+	// Overflow is a policy of the queue without lanes.
+	Overflow Overflow
+	// Lanes are priority classes of messages.
+	// If empty, a queue has a single DefaultLane limited by MaxLength.
+	Lanes []Lane
 }
 
 type Option func(*Options)
@@ -51,6 +157,19 @@ func WithInputChanLen(chanLen int) Option {
 	}
 }
 
+func WithOverflow(overflow Overflow) Option {
+	return func(o *Options) {
+		o.Overflow = overflow
+	}
+}
+
+// WithLanes splits a queue into lanes, the first lane is the input returned by Input.
+func WithLanes(lanes ...Lane) Option {
+	return func(o *Options) {
+		o.Lanes = lanes
+	}
+}
+
 func NewQueue[T any](name string, opts ...Option) *ElasticQueue[T] {
 	options := Options{
 		MaxLength: 0,
@@ -58,16 +177,58 @@ func NewQueue[T any](name string, opts ...Option) *ElasticQueue[T] {
 	for _, opt := range opts {
 		opt(&options)
 	}
-	return &ElasticQueue[T]{
-		name:      name,
-		input:     make(chan T, options.InputQueueChanLen),
-		output:    make(chan T),
-		maxLength: options.MaxLength,
+	lanes := options.Lanes
+	if len(lanes) == 0 {
+		lanes = []Lane{{Name: DefaultLane, MaxLength: options.MaxLength, Overflow: options.Overflow}}
 	}
+	q := &ElasticQueue[T]{
+		name:   name,
+		output: make(chan T),
+	}
+	for _, l := range lanes {
+		q.lanes = append(q.lanes, &lane[T]{
+			name:      l.Name,
+			input:     make(chan T, options.InputQueueChanLen),
+			maxLength: l.MaxLength,
+			overflow:  l.Overflow,
+			stride:    strideScale / uint64(max(l.Weight, 1)),
+			// a bounded lane never grows, so its buffer is allocated once.
+			msgs:  newRing[message[T]](l.MaxLength),
+			space: make(chan struct{}, 1),
+		})
+	}
+	return q
 }
 
+// Input returns the input of the first lane.
 func (q *ElasticQueue[T]) Input() chan<- T {
-	return q.input
+	return q.lanes[0].input
+}
+
+// LaneInput returns the input of the i-th lane in the order of WithLanes.
+func (q *ElasticQueue[T]) LaneInput(i int) chan<- T {
+	return q.lanes[i].input
+}
+
+// Lanes returns the number of lanes.
+func (q *ElasticQueue[T]) Lanes() int {
+	return len(q.lanes)
+}
+
+// Len returns the number of queued messages in all lanes.
+func (q *ElasticQueue[T]) Len() int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.length()
+}
+
+// length returns the number of queued messages in all lanes, q.mu must be held.
+func (q *ElasticQueue[T]) length() int {
+	length := 0
+	for _, l := range q.lanes {
+		length += l.len()
+	}
+	return length
 }
 
 func (q *ElasticQueue[T]) Output() <-chan T {
@@ -79,35 +240,209 @@ type message[T any] struct {
 	value T
 }
 
+// Run moves messages from inputs to the output until the context is canceled.
+// It returns after all goroutines it has started are done.
 func (q *ElasticQueue[T]) Run(ctx context.Context) {
-	// TODO: optimize storage to avoid memory allocations/reallocations
-	var msgs []message[T]
+	// ready is signaled when a message is queued, so the next message is picked again.
+	ready := make(chan struct{}, 1)
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	for _, l := range q.lanes {
+		wg.Add(1)
+		go func(l *lane[T]) {
+			defer wg.Done()
+			q.receive(ctx, l, ready)
+		}(l)
+	}
 	for {
-		if len(msgs) == 0 {
+		q.mu.Lock()
+		l := q.next()
+		var msg message[T]
+		if l != nil {
+			msg = l.msgs.pop()
+			l.sending = true
+		}
+		q.mu.Unlock()
+
+		if l == nil {
 			select {
 			case <-ctx.Done():
 				return
-			case msg := <-q.input:
-				msgs = append(msgs, message[T]{value: msg, recv: time.Now()})
+			case <-ready:
 			}
+			continue
 		}
-
-		input := q.input
-		// maxLength = 0 means a queue is unlimited
-		if q.maxLength > 0 && q.maxLength <= len(msgs) {
-			input = nil
+		if l.overflow == OverflowDropExpired && expired(msg.value, time.Now()) {
+			q.mu.Lock()
+			l.sending = false
+			q.drop(l, "expired", 1)
+			q.mu.Unlock()
+			notify(l.space)
+			continue
+		}
+		select {
+		case <-ctx.Done():
+			q.mu.Lock()
+			l.msgs.pushFront(msg)
+			l.sending = false
+			q.mu.Unlock()
+			return
+		case <-ready:
+			// a message of a lane with a lower pass might have arrived.
+			q.mu.Lock()
+			l.msgs.pushFront(msg)
+			l.sending = false
+			q.mu.Unlock()
+		case q.output <- msg.value:
+			q.mu.Lock()
+			l.sending = false
+			q.pass = l.pass
+			l.pass += l.stride
+			q.setLength(l)
+			q.mu.Unlock()
+			notify(l.space)
+			wait := time.Since(msg.recv).Seconds()
+			queueTimeHistogramVec.WithLabelValues(q.name).Observe(wait)
+			queueLaneTimeHistogramVec.WithLabelValues(q.name, l.name).Observe(wait)
+			q.traceWait(l, msg)
 		}
+	}
+}
 
+// receive moves messages from the input of a lane to the lane.
+func (q *ElasticQueue[T]) receive(ctx context.Context, l *lane[T], ready chan<- struct{}) {
+	for {
+		q.mu.Lock()
+		blocked := q.blocked(l)
+		q.mu.Unlock()
+		if blocked {
+			select {
+			case <-ctx.Done():
+				return
+			case <-l.space:
+			}
+			continue
+		}
 		select {
 		case <-ctx.Done():
 			return
-		case msg := <-input:
-			msgs = append(msgs, message[T]{value: msg, recv: time.Now()})
-		case q.output <- msgs[0].value:
-			delta := time.Since(msgs[0].recv)
-			msgs = msgs[1:]
-			queueTimeHistogramVec.WithLabelValues(q.name).Observe(delta.Seconds())
+		case value := <-l.input:
+			q.mu.Lock()
+			queued := q.add(l, value)
+			q.mu.Unlock()
+			if queued {
+				notify(ready)
+			}
 		}
-		queueLength.WithLabelValues(q.name).Set(float64(len(msgs)))
+	}
+}
+
+func (l *lane[T]) len() int {
+	if l.sending {
+		return l.msgs.len() + 1
+	}
+	return l.msgs.len()
+}
+
+func (l *lane[T]) full() bool {
+	// maxLength = 0 means a lane is unlimited
+	return l.maxLength > 0 && l.maxLength <= l.len()
+}
+
+// blocked reports whether the input of a lane must not be read.
+func (q *ElasticQueue[T]) blocked(l *lane[T]) bool {
+	if !l.full() {
+		return false
+	}
+	switch l.overflow {
+	case OverflowRejectNewest, OverflowDropOldest:
+		return false
+	case OverflowDropExpired:
+		now := time.Now()
+		removed := l.msgs.filter(func(msg message[T]) bool {
+			return !expired(msg.value, now)
+		})
+		q.drop(l, "expired", removed)
+		return l.full()
+	default:
+		return true
+	}
+}
+
+// add queues a message according to the overflow policy of a lane and reports whether it is queued.
+func (q *ElasticQueue[T]) add(l *lane[T], value T) bool {
+	if l.full() {
+		switch l.overflow {
+		case OverflowRejectNewest:
+			q.drop(l, "rejected", 1)
+			return false
+		case OverflowDropOldest:
+			// the message being sent can't be dropped, so a lane of one message grows instead.
+			if l.msgs.len() > 0 {
+				l.msgs.pop()
+				q.drop(l, "oldest", 1)
+			}
+		}
+	}
+	if l.len() == 0 {
+		// an idle lane doesn't save up its share of the output.
+		l.pass = max(l.pass, q.pass)
+	}
+	l.msgs.push(message[T]{value: value, recv: time.Now()})
+	q.setLength(l)
+	return true
+}
+
+func (q *ElasticQueue[T]) drop(l *lane[T], reason string, count int) {
+	if count == 0 {
+		return
+	}
+	queueDropped.WithLabelValues(q.name, l.name, reason).Add(float64(count))
+	q.setLength(l)
+}
+
+// setLength updates the length metrics after a change of the given lane, q.mu must be held.
+func (q *ElasticQueue[T]) setLength(l *lane[T]) {
+	queueLaneLength.WithLabelValues(q.name, l.name).Set(float64(l.len()))
+	queueLength.WithLabelValues(q.name).Set(float64(q.length()))
+}
+
+// next returns a non-empty lane with the lowest pass, lanes declared first win ties.
+func (q *ElasticQueue[T]) next() *lane[T] {
+	var next *lane[T]
+	for _, l := range q.lanes {
+		if l.msgs.len() > 0 && (next == nil || l.pass < next.pass) {
+			next = l
+		}
+	}
+	return next
+}
+
+func expired[T any](value T, now time.Time) bool {
+	d, ok := any(value).(Deadliner)
+	if !ok {
+		return false
+	}
+	deadline, ok := d.Deadline()
+	return ok && !now.Before(deadline)
+}
+
+func (q *ElasticQueue[T]) traceWait(l *lane[T], msg message[T]) {
+	c, ok := any(msg.value).(Contexter)
+	if !ok || c.Context() == nil {
+		return
+	}
+	_, span := tracer.Start(c.Context(), "queue.wait", trace.WithTimestamp(msg.recv), trace.WithAttributes(
+		attribute.String("queue", q.name),
+		attribute.String("lane", l.name),
+	))
+	span.End()
+}
+
+// notify signals a channel with a buffer of one without blocking.
+func notify(ch chan<- struct{}) {
+	select {
+	case ch <- struct{}{}:
+	default:
 	}
 }
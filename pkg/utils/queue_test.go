@@ -0,0 +1,295 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/goleak"
+)
+
+// TestMain checks that every queue stops all its goroutines once its context is canceled.
+func TestMain(m *testing.M) {
+	goleak.VerifyTestMain(m)
+}
+
+// queued returns the number of messages in every lane of a queue.
+func queued[T any](q *ElasticQueue[T]) []int {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var lengths []int
+	for _, l := range q.lanes {
+		lengths = append(lengths, l.len())
+	}
+	return lengths
+}
+
+// sending reports whether the head of the first lane is being sent to the output.
+func sending[T any](q *ElasticQueue[T]) bool {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	return q.lanes[0].sending
+}
+
+func TestElasticQueue_lanes(t *testing.T) {
+	q := NewQueue[int]("test", WithLanes(
+		Lane{Name: "fast", Weight: 3},
+		Lane{Name: "slow", Weight: 1},
+	))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go q.Run(ctx)
+
+	for i := 0; i < 40; i++ {
+		q.LaneInput(1) <- 100 + i
+	}
+	for i := 0; i < 40; i++ {
+		q.Input() <- i
+	}
+	require.Eventually(t, func() bool {
+		lengths := queued(q)
+		return lengths[0] == 40 && lengths[1] == 40
+	}, time.Second, time.Millisecond)
+	// queue_length keeps its labels and counts all lanes, the lanes have their own series.
+	require.Equal(t, 80.0, testutil.ToFloat64(queueLength.WithLabelValues("test")))
+	require.Equal(t, 40.0, testutil.ToFloat64(queueLaneLength.WithLabelValues("test", "fast")))
+	require.Equal(t, 40.0, testutil.ToFloat64(queueLaneLength.WithLabelValues("test", "slow")))
+
+	var fast, slow []int
+	for i := 0; i < 40; i++ {
+		if value := <-q.Output(); value < 100 {
+			fast = append(fast, value)
+		} else {
+			slow = append(slow, value)
+		}
+	}
+	// lanes share the output in proportion to their weights and every lane is FIFO.
+	require.InDelta(t, 30, len(fast), 1)
+	require.InDelta(t, 10, len(slow), 1)
+	for i, value := range fast {
+		require.Equal(t, i, value)
+	}
+	for i, value := range slow {
+		require.Equal(t, 100+i, value)
+	}
+
+	// the rest of the slow lane isn't starved once the fast lane is empty.
+	for i := 0; i < 40; i++ {
+		<-q.Output()
+	}
+	require.Equal(t, []int{0, 0}, queued(q))
+}
+
+func TestElasticQueue_maxLength(t *testing.T) {
+	q := NewQueue[int]("test", WithLanes(
+		Lane{Name: "limited", MaxLength: 2},
+		Lane{Name: "unlimited"},
+	))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go q.Run(ctx)
+
+	q.Input() <- 1
+	q.Input() <- 2
+	select {
+	case q.Input() <- 3:
+		t.Fatal("a full lane accepted a message")
+	case <-time.After(50 * time.Millisecond):
+	}
+	// a full lane doesn't block the others.
+	for i := 0; i < 10; i++ {
+		q.LaneInput(1) <- i
+	}
+
+	for i := 0; i < 12; i++ {
+		<-q.Output()
+	}
+	q.Input() <- 3
+	require.Equal(t, 3, <-q.Output())
+}
+
+func TestElasticQueue_defaultLane(t *testing.T) {
+	q := NewQueue[int]("test", WithMaxLength(1))
+	require.Equal(t, 1, q.Lanes())
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		q.Run(ctx)
+		close(done)
+	}()
+	q.Input() <- 1
+	require.Equal(t, 1, <-q.Output())
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Run doesn't return after cancellation")
+	}
+}
+
+type deadlined struct {
+	value    int
+	deadline time.Time
+}
+
+func (d deadlined) Deadline() (time.Time, bool) {
+	return d.deadline, !d.deadline.IsZero()
+}
+
+func TestElasticQueue_overflow(t *testing.T) {
+	tests := []struct {
+		name     string
+		overflow Overflow
+		reason   string
+		want     []int
+	}{
+		{
+			name:     "reject newest",
+			overflow: OverflowRejectNewest,
+			reason:   "rejected",
+			want:     []int{1, 2},
+		},
+		{
+			name:     "drop oldest",
+			overflow: OverflowDropOldest,
+			reason:   "oldest",
+			// the first message is already being sent, so it isn't dropped.
+			want: []int{1, 4},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			q := NewQueue[int](tt.name, WithMaxLength(2), WithOverflow(tt.overflow))
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go q.Run(ctx)
+			dropped := testutil.ToFloat64(queueDropped.WithLabelValues(tt.name, DefaultLane, tt.reason))
+
+			q.Input() <- 1
+			require.Eventually(t, func() bool { return sending(q) }, time.Second, time.Millisecond)
+			for i := 2; i <= 4; i++ {
+				q.Input() <- i
+			}
+			require.Eventually(t, func() bool {
+				return testutil.ToFloat64(queueDropped.WithLabelValues(tt.name, DefaultLane, tt.reason)) == dropped+2
+			}, time.Second, time.Millisecond)
+
+			var values []int
+			for range tt.want {
+				values = append(values, <-q.Output())
+			}
+			require.Equal(t, tt.want, values)
+			require.Equal(t, []int{0}, queued(q))
+		})
+	}
+}
+
+func TestElasticQueue_dropExpired(t *testing.T) {
+	q := NewQueue[deadlined]("expired", WithMaxLength(2), WithOverflow(OverflowDropExpired))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go q.Run(ctx)
+	before := testutil.ToFloat64(queueDropped.WithLabelValues("expired", DefaultLane, "expired"))
+	dropped := func() float64 {
+		return testutil.ToFloat64(queueDropped.WithLabelValues("expired", DefaultLane, "expired")) - before
+	}
+
+	past := time.Now().Add(-time.Minute)
+	q.Input() <- deadlined{value: 1}
+	require.Eventually(t, func() bool { return sending(q) }, time.Second, time.Millisecond)
+	q.Input() <- deadlined{value: 2, deadline: past}
+	// the lane is full, so the expired message makes space for the next one.
+	q.Input() <- deadlined{value: 3, deadline: time.Now().Add(time.Minute)}
+	require.Equal(t, float64(1), dropped())
+	require.Equal(t, 1, (<-q.Output()).value)
+	require.Equal(t, 3, (<-q.Output()).value)
+
+	// an expired message is never dequeued.
+	q.Input() <- deadlined{value: 4, deadline: past}
+	q.Input() <- deadlined{value: 5}
+	require.Equal(t, 5, (<-q.Output()).value)
+	require.Equal(t, float64(2), dropped())
+
+	// the lane blocks if nothing has expired.
+	q.Input() <- deadlined{value: 6}
+	require.Eventually(t, func() bool { return sending(q) }, time.Second, time.Millisecond)
+	q.Input() <- deadlined{value: 7}
+	select {
+	case q.Input() <- deadlined{value: 8}:
+		t.Fatal("a full lane accepted a message")
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestElasticQueue_cancel(t *testing.T) {
+	for _, overflow := range []Overflow{OverflowBlock, OverflowRejectNewest, OverflowDropOldest, OverflowDropExpired} {
+		q := NewQueue[int]("test", WithLanes(
+			Lane{Name: "first", MaxLength: 1, Overflow: overflow},
+			Lane{Name: "second", Weight: 2},
+		))
+		ctx, cancel := context.WithCancel(context.Background())
+		done := make(chan struct{})
+		go func() {
+			q.Run(ctx)
+			close(done)
+		}()
+		q.Input() <- 1
+		q.LaneInput(1) <- 2
+		cancel()
+		<-done
+		// queued messages stay in the queue.
+		require.Equal(t, 2, queued(q)[0]+queued(q)[1])
+	}
+}
+
+func BenchmarkElasticQueue(b *testing.B) {
+	for _, maxLength := range []int{0, 1000} {
+		b.Run(fmt.Sprintf("maxLength=%v", maxLength), func(b *testing.B) {
+			q := NewQueue[int]("bench", WithMaxLength(maxLength))
+			ctx, cancel := context.WithCancel(context.Background())
+			defer cancel()
+			go q.Run(ctx)
+			b.ReportAllocs()
+			b.ResetTimer()
+			go func() {
+				for i := 0; i < b.N; i++ {
+					q.Input() <- i
+				}
+			}()
+			for i := 0; i < b.N; i++ {
+				<-q.Output()
+			}
+		})
+	}
+}
+
+func BenchmarkElasticQueue_lanes(b *testing.B) {
+	q := NewQueue[int]("bench", WithLanes(
+		Lane{Name: "fast", Weight: 10, MaxLength: 1000},
+		Lane{Name: "slow", Weight: 1, MaxLength: 1000},
+	))
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go q.Run(ctx)
+	b.ReportAllocs()
+	b.ResetTimer()
+	var wg sync.WaitGroup
+	for lane := 0; lane < q.Lanes(); lane++ {
+		wg.Add(1)
+		go func(input chan<- int) {
+			defer wg.Done()
+			for i := 0; i < b.N/2; i++ {
+				input <- i
+			}
+		}(q.LaneInput(lane))
+	}
+	for i := 0; i < b.N/2*2; i++ {
+		<-q.Output()
+	}
+	wg.Wait()
+}
@@ -0,0 +1,78 @@
+package utils
+
+// minRingSize is the capacity of a ring allocated on the first push.
+const minRingSize = 16
+
+// ring is a FIFO circular buffer growing when it is full.
+// Unlike reslicing, popped slots are reused, so a ring of a steady size doesn't allocate.
+type ring[T any] struct {
+	buf   []T
+	head  int
+	count int
+}
+
+func newRing[T any](capacity int) ring[T] {
+	return ring[T]{buf: make([]T, capacity)}
+}
+
+func (r *ring[T]) len() int {
+	return r.count
+}
+
+func (r *ring[T]) push(value T) {
+	if r.count == len(r.buf) {
+		r.grow()
+	}
+	r.buf[(r.head+r.count)%len(r.buf)] = value
+	r.count++
+}
+
+// pushFront puts a value back to the head of the ring.
+func (r *ring[T]) pushFront(value T) {
+	if r.count == len(r.buf) {
+		r.grow()
+	}
+	r.head = (r.head + len(r.buf) - 1) % len(r.buf)
+	r.buf[r.head] = value
+	r.count++
+}
+
+// pop removes the head of the ring, the ring must not be empty.
+func (r *ring[T]) pop() T {
+	var zero T
+	value := r.buf[r.head]
+	// the slot is cleared, so the ring doesn't keep popped values alive.
+	r.buf[r.head] = zero
+	r.head = (r.head + 1) % len(r.buf)
+	r.count--
+	return value
+}
+
+// filter removes values for which keep returns false, the order of the rest is preserved.
+// It returns the number of removed values.
+func (r *ring[T]) filter(keep func(T) bool) int {
+	var zero T
+	kept := 0
+	for i := 0; i < r.count; i++ {
+		value := r.buf[(r.head+i)%len(r.buf)]
+		if keep(value) {
+			r.buf[(r.head+kept)%len(r.buf)] = value
+			kept++
+		}
+	}
+	for i := kept; i < r.count; i++ {
+		r.buf[(r.head+i)%len(r.buf)] = zero
+	}
+	removed := r.count - kept
+	r.count = kept
+	return removed
+}
+
+func (r *ring[T]) grow() {
+	buf := make([]T, max(2*len(r.buf), minRingSize))
+	for i := 0; i < r.count; i++ {
+		buf[i] = r.buf[(r.head+i)%len(r.buf)]
+	}
+	r.buf = buf
+	r.head = 0
+}
@@ -0,0 +1,61 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func drainRing[T any](r *ring[T]) []T {
+	var values []T
+	for r.len() > 0 {
+		values = append(values, r.pop())
+	}
+	return values
+}
+
+func TestRing(t *testing.T) {
+	r := newRing[int](0)
+	for i := 0; i < 100; i++ {
+		r.push(i)
+		if i%3 == 0 {
+			require.Equal(t, i/3, r.pop())
+		}
+	}
+	r.pushFront(-1)
+	values := drainRing(&r)
+	require.Equal(t, -1, values[0])
+	for i, value := range values[1:] {
+		require.Equal(t, 34+i, value)
+	}
+
+	// a bounded ring wraps around without growing.
+	r = newRing[int](4)
+	for i := 0; i < 10; i++ {
+		r.push(i)
+		r.push(i)
+		r.pop()
+		r.pop()
+	}
+	require.Equal(t, 4, len(r.buf))
+
+	for i := 0; i < 4; i++ {
+		r.push(i)
+	}
+	require.Equal(t, 2, r.filter(func(value int) bool { return value%2 == 0 }))
+	require.Equal(t, []int{0, 2}, drainRing(&r))
+	require.Equal(t, []int{0, 0, 0, 0}, r.buf)
+}
+
+func BenchmarkRing(b *testing.B) {
+	r := newRing[int](1024)
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		r.push(i)
+		if r.len() == 1024 {
+			for r.len() > 0 {
+				r.pop()
+			}
+		}
+	}
+}